@@ -0,0 +1,26 @@
+# Run on demand with: terraform apply -invoke=action.simplemdm_device_command.refresh
+action "simplemdm_device_command" "refresh" {
+  config {
+    device_id = "123456"
+    command   = "refresh"
+  }
+}
+
+# Push the assigned apps again whenever the group membership changes.
+resource "simplemdm_devicegroup" "kiosks" {
+  name = "Kiosks"
+
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.simplemdm_device_command.push_apps]
+    }
+  }
+}
+
+action "simplemdm_device_command" "push_apps" {
+  config {
+    device_id = "123456"
+    command   = "push_assigned_apps"
+  }
+}
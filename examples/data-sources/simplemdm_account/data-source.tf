@@ -0,0 +1,13 @@
+provider "simplemdm" {
+  alias                 = "staging"
+  apikey                = var.staging_api_key
+  expected_account_name = "Example Staging"
+}
+
+data "simplemdm_account" "staging" {
+  provider = simplemdm.staging
+}
+
+output "staging_available_licenses" {
+  value = data.simplemdm_account.staging.licenses_available
+}
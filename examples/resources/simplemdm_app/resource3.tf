@@ -1,8 +1,18 @@
 # Upload a custom enterprise or macOS package app by providing a binary file.
 # The provider will post the binary to SimpleMDM and keep the metadata in sync.
+# Rebuilding the file at the same path changes binary_sha256, which uploads the
+# new binary in place. local_bundle_id and local_version are read from the file
+# when planning, and a binary of a different app is rejected before upload.
+# Create and update wait until SimpleMDM has processed the binary, so resources
+# referencing the app see a processed app.
 resource "simplemdm_app" "enterprise" {
   name        = "Internal Tools"
   binary_file = "${path.module}/files/internal-tools.pkg"
+
+  timeouts {
+    create = "30m"
+    update = "30m"
+  }
 }
 
 output "enterprise_processing_status" {
@@ -0,0 +1,14 @@
+# macOS package apps are installed through Munki, which can be tuned per app.
+# Empty strings and lists clear a setting. These settings cannot be combined
+# with app_store_id or bundle_id.
+resource "simplemdm_app" "munki" {
+  name        = "Design Suite"
+  binary_file = "${path.module}/files/design-suite.pkg"
+
+  installer_type        = "package" // Valid values: "package", "copy_from_dmg", "nopkg".
+  preinstall_script     = file("${path.module}/files/preinstall.sh")
+  postinstall_script    = file("${path.module}/files/postinstall.sh")
+  uninstall_method      = "removepackages" // Valid values: "removepackages", "remove_copied_items", "remove_app", "uninstall_script".
+  blocking_applications = ["Design Suite", "Design Suite Helper"]
+  minimum_os_version    = "13.0"
+}
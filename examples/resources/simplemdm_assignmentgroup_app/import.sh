@@ -0,0 +1,2 @@
+# App assignments can be imported by specifying the Assignment group ID and the App ID separated by a colon.
+terraform import simplemdm_assignmentgroup_app.example 123456:987654
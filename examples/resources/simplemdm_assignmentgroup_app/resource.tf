@@ -0,0 +1,16 @@
+resource "simplemdm_assignmentgroup" "munki" {
+  name = "Munki apps"
+
+  // Leave apps unset so the assignments below own the group's apps
+}
+
+resource "simplemdm_assignmentgroup_app" "browser" {
+  group_id = simplemdm_assignmentgroup.munki.id
+  app_id   = "123456"
+
+  // Valid values: "managed", "self_serve", "managed_updates", "default_installs"
+  install_type = "self_serve"
+
+  // Send a Push Apps command once the app is assigned, default is false
+  deploy_on_create = true
+}
@@ -0,0 +1,16 @@
+resource "simplemdm_bulk_device_command" "push_apps" {
+  command          = "push_assigned_apps"
+  device_group_ids = ["12345"]
+
+  triggers = {
+    app_version = "2.4.1"
+  }
+}
+
+output "failed_devices" {
+  description = "Devices the command could not be sent to"
+  value = [
+    for result in simplemdm_bulk_device_command.push_apps.results : result.device_id
+    if result.status == "failed"
+  ]
+}
@@ -0,0 +1,11 @@
+# Advanced Example - Schedule an OS update on every matching device
+resource "simplemdm_bulk_device_command" "update_os" {
+  command              = "update_os"
+  assignment_group_ids = ["67890"]
+  search               = "MacBook"
+  max_concurrency      = 10
+
+  parameters = {
+    os_update_mode = "smart_update"
+  }
+}
@@ -1,7 +1,10 @@
 # Advanced Example - Clear passcode command
+# clear_passcode is destructive: it must be listed in the provider's
+# allowed_destructive_commands and the device must match the confirmation.
 resource "simplemdm_device_command" "clear_passcode" {
-  device_id = "123456"
-  command   = "clear_passcode"
+  device_id             = "123456"
+  command               = "clear_passcode"
+  confirm_device_serial = "C02XK1ABCDEF"
 }
 
 # Advanced Example - Update inventory command
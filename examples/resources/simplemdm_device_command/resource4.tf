@@ -0,0 +1,19 @@
+# Advanced Example - Update the OS and wait for the new version
+resource "simplemdm_device_command" "update_os" {
+  device_id = "123456"
+  command   = "update_os"
+
+  parameters = {
+    os_update_mode = "install_asap"
+  }
+
+  wait_for {
+    timeout       = 3600
+    poll_interval = 60
+  }
+}
+
+output "updated_os_version" {
+  description = "OS version the device reported after the update"
+  value       = simplemdm_device_command.update_os.observed_os_version
+}
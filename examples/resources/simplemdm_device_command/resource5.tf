@@ -0,0 +1,11 @@
+# Advanced Example - Push apps again whenever app_version changes
+resource "simplemdm_device_command" "push_apps" {
+  device_id = "123456"
+  command   = "push_assigned_apps"
+
+  triggers = {
+    app_version = "2.4.1"
+  }
+
+  history_limit = 5
+}
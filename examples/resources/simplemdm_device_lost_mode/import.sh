@@ -0,0 +1,2 @@
+# device_lost_mode can be imported by specifying the device ID.
+terraform import simplemdm_device_lost_mode.stolen_ipad 123456
@@ -0,0 +1,22 @@
+resource "simplemdm_device_lost_mode" "stolen_ipad" {
+  device_id    = "123456"
+  message      = "This iPad has been reported lost. Please call the number below."
+  phone_number = "+15555551234"
+  footnote     = "Property of Example Corp"
+
+  # Change a value to play a sound or request a fresh location.
+  play_sound_triggers = {
+    requested = "2024-05-01"
+  }
+  update_location_triggers = {
+    requested = "2024-05-01"
+  }
+}
+
+output "last_known_location" {
+  value = {
+    latitude   = simplemdm_device_lost_mode.stolen_ipad.location_latitude
+    longitude  = simplemdm_device_lost_mode.stolen_ipad.location_longitude
+    updated_at = simplemdm_device_lost_mode.stolen_ipad.location_updated_at
+  }
+}
@@ -12,6 +12,12 @@ type EndpointCoverage struct {
 }
 
 var Catalog = []EndpointCoverage{
+	{
+		Name:           "Account",
+		Endpoint:       "/api/v1/account",
+		DataSourceType: "simplemdm_account",
+		DocsURL:        "https://api.simplemdm.com/v1/#tag/Account",
+	},
 	{
 		Name:           "Apps",
 		Endpoint:       "/api/v1/apps",
@@ -258,4 +264,28 @@ var Catalog = []EndpointCoverage{
 		ResourceType: "simplemdm_device_command",
 		DocsURL:      "https://api.simplemdm.com/v1/#tag/Devices",
 	},
+	{
+		Name:         "Device Lost Mode - Enable",
+		Endpoint:     "/api/v1/devices/{DEVICE_ID}/lost_mode (POST)",
+		ResourceType: "simplemdm_device_lost_mode",
+		DocsURL:      "https://api.simplemdm.com/v1/#tag/Lost-Mode",
+	},
+	{
+		Name:         "Device Lost Mode - Disable",
+		Endpoint:     "/api/v1/devices/{DEVICE_ID}/lost_mode (DELETE)",
+		ResourceType: "simplemdm_device_lost_mode",
+		DocsURL:      "https://api.simplemdm.com/v1/#tag/Lost-Mode",
+	},
+	{
+		Name:         "Device Lost Mode - Play Sound",
+		Endpoint:     "/api/v1/devices/{DEVICE_ID}/lost_mode/play_sound",
+		ResourceType: "simplemdm_device_lost_mode",
+		DocsURL:      "https://api.simplemdm.com/v1/#tag/Lost-Mode",
+	},
+	{
+		Name:         "Device Lost Mode - Update Location",
+		Endpoint:     "/api/v1/devices/{DEVICE_ID}/lost_mode/update_location",
+		ResourceType: "simplemdm_device_lost_mode",
+		DocsURL:      "https://api.simplemdm.com/v1/#tag/Lost-Mode",
+	},
 }
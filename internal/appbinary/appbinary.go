@@ -0,0 +1,95 @@
+// Package appbinary reads the bundle identifier and version of app binaries
+// locally, so they are known before a binary is uploaded to SimpleMDM. It
+// understands iOS app archives (.ipa) and flat macOS installer packages
+// (.pkg).
+package appbinary
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// maxMetadataSize caps how much of a metadata file, such as an Info.plist or
+// a package Distribution, is read.
+const maxMetadataSize = 8 << 20
+
+// ErrUnsupported is returned for files that are neither an app archive nor a
+// flat package.
+var ErrUnsupported = errors.New("unsupported app binary format")
+
+// Metadata describes the app contained in a binary.
+type Metadata struct {
+	BundleID string
+	Version  string
+}
+
+// Inspect reads the metadata of the app binary at path.
+func Inspect(path string) (_ Metadata, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	return Read(file, info.Size())
+}
+
+// Read reads the metadata of an app binary of the given size. The format is
+// recognised from the content rather than the file name.
+func Read(r io.ReaderAt, size int64) (Metadata, error) {
+	magic := make([]byte, 4)
+	if _, err := r.ReadAt(magic, 0); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Metadata{}, ErrUnsupported
+		}
+		return Metadata{}, err
+	}
+
+	var (
+		metadata Metadata
+		err      error
+	)
+	switch {
+	case bytes.Equal(magic, []byte("PK\x03\x04")):
+		metadata, err = readIPA(r, size)
+	case bytes.Equal(magic, []byte(xarMagic)):
+		metadata, err = readPkg(r, size)
+	default:
+		return Metadata{}, ErrUnsupported
+	}
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	if metadata.BundleID == "" {
+		return Metadata{}, errors.New("no bundle identifier found")
+	}
+
+	return metadata, nil
+}
+
+// readLimited reads all of r, failing rather than truncating when it holds
+// more than maxMetadataSize bytes.
+func readLimited(r io.Reader, name string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxMetadataSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	if len(data) > maxMetadataSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, maxMetadataSize)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,268 @@
+package appbinary
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func xmlPlist(bundleID, version string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleIcons</key>
+	<dict>
+		<key>CFBundleIdentifier</key>
+		<string>com.example.nested</string>
+	</dict>
+	<key>CFBundleIdentifier</key>
+	<string>` + bundleID + `</string>
+	<key>CFBundleShortVersionString</key>
+	<string>` + version + `</string>
+	<key>LSRequiresIPhoneOS</key>
+	<true/>
+</dict>
+</plist>`
+}
+
+// encodeBinaryPlist encodes a bplist00 dictionary. Values are strings or small
+// integers.
+func encodeBinaryPlist(keys []string, values []any) []byte {
+	encodeString := func(s string) []byte {
+		if len(s) < 15 {
+			return append([]byte{0x50 | byte(len(s))}, s...)
+		}
+		return append([]byte{0x5F, 0x10, byte(len(s))}, s...)
+	}
+
+	dict := []byte{0xD0 | byte(len(keys))}
+	for i := range 2 * len(keys) {
+		dict = append(dict, byte(1+i))
+	}
+	objects := [][]byte{dict}
+	for _, key := range keys {
+		objects = append(objects, encodeString(key))
+	}
+	for _, value := range values {
+		switch value := value.(type) {
+		case string:
+			objects = append(objects, encodeString(value))
+		case int:
+			objects = append(objects, []byte{0x10, byte(value)})
+		}
+	}
+
+	data := []byte(binaryPlistMagic)
+	var offsets []byte
+	for _, object := range objects {
+		offsets = append(offsets, byte(len(data)))
+		data = append(data, object...)
+	}
+	offsetTable := len(data)
+	data = append(data, offsets...)
+
+	trailer := make([]byte, binaryPlistTrailerSize)
+	trailer[6], trailer[7] = 1, 1
+	binary.BigEndian.PutUint64(trailer[8:], uint64(len(objects)))
+	binary.BigEndian.PutUint64(trailer[24:], uint64(offsetTable))
+
+	return append(data, trailer...)
+}
+
+// archive returns a zip file holding the given files in order.
+func archive(t *testing.T, files ...[2]string) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	for _, file := range files {
+		w, err := writer.Create(file[0])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, _ = w.Write([]byte(file[1]))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// flatPackage returns a XAR archive holding the given files, compressed like
+// pkgbuild and productbuild do. Files may be one directory deep.
+func flatPackage(t *testing.T, files ...[2]string) []byte {
+	t.Helper()
+
+	heap := &bytes.Buffer{}
+	toc := &strings.Builder{}
+	toc.WriteString(`<?xml version="1.0" encoding="UTF-8"?><xar><toc>`)
+	for i, file := range files {
+		compressed := &bytes.Buffer{}
+		w := zlib.NewWriter(compressed)
+		_, _ = w.Write([]byte(file[1]))
+		_ = w.Close()
+
+		dir, name := path.Split(file[0])
+		entry := fmt.Sprintf(`<file id="%d"><name>%s</name><type>file</type><data><offset>%d</offset><length>%d</length><size>%d</size><encoding style="application/x-gzip"/></data></file>`,
+			2*i+1, name, heap.Len(), compressed.Len(), len(file[1]))
+		if dir != "" {
+			entry = fmt.Sprintf(`<file id="%d"><name>%s</name><type>directory</type>%s</file>`, 2*i+2, path.Clean(dir), entry)
+		}
+		toc.WriteString(entry)
+		heap.Write(compressed.Bytes())
+	}
+	toc.WriteString(`</toc></xar>`)
+
+	compressedTOC := &bytes.Buffer{}
+	w := zlib.NewWriter(compressedTOC)
+	_, _ = w.Write([]byte(toc.String()))
+	_ = w.Close()
+
+	header := make([]byte, xarHeaderSize)
+	copy(header, xarMagic)
+	binary.BigEndian.PutUint16(header[4:], xarHeaderSize)
+	binary.BigEndian.PutUint16(header[6:], 1)
+	binary.BigEndian.PutUint64(header[8:], uint64(compressedTOC.Len()))
+	binary.BigEndian.PutUint64(header[16:], uint64(toc.Len()))
+
+	return append(append(header, compressedTOC.Bytes()...), heap.Bytes()...)
+}
+
+func read(t *testing.T, data []byte) (Metadata, error) {
+	t.Helper()
+
+	return Read(bytes.NewReader(data), int64(len(data)))
+}
+
+func TestReadIPAWithXMLPlist(t *testing.T) {
+	ipa := archive(t,
+		[2]string{"Payload/Tools.app/PlugIns/Widget.appex/Info.plist", xmlPlist("com.example.tools.widget", "9.9")},
+		[2]string{"Payload/Tools.app/Info.plist", xmlPlist("com.example.tools", "2.3.1")},
+	)
+
+	metadata, err := read(t, ipa)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata != (Metadata{BundleID: "com.example.tools", Version: "2.3.1"}) {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestReadIPAWithBinaryPlist(t *testing.T) {
+	plist := encodeBinaryPlist(
+		[]string{"CFBundleIdentifier", "CFBundleVersion", "UIDeviceFamily"},
+		[]any{"com.example.binary", "417", 1},
+	)
+	ipa := archive(t, [2]string{"Payload/Binary.app/Info.plist", string(plist)})
+
+	metadata, err := read(t, ipa)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata != (Metadata{BundleID: "com.example.binary", Version: "417"}) {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestReadIPAWithoutApp(t *testing.T) {
+	ipa := archive(t, [2]string{"Payload/readme.txt", "nothing here"})
+
+	if _, err := read(t, ipa); err == nil || !strings.Contains(err.Error(), "no Payload/*.app/Info.plist") {
+		t.Fatalf("expected a missing Info.plist error, got %v", err)
+	}
+}
+
+func TestReadProductArchive(t *testing.T) {
+	pkg := flatPackage(t,
+		[2]string{"Tools.pkg/PackageInfo", `<pkg-info identifier="com.example.tools.component" version="1.0"/>`},
+		[2]string{"Distribution", `<?xml version="1.0" encoding="utf-8"?>
+<installer-gui-script minSpecVersion="2">
+	<product id="com.example.tools" version="4.2"/>
+	<pkg-ref id="com.example.tools.component" version="1.0">#Tools.pkg</pkg-ref>
+</installer-gui-script>`},
+	)
+
+	metadata, err := read(t, pkg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata != (Metadata{BundleID: "com.example.tools", Version: "4.2"}) {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestReadProductArchiveWithoutProduct(t *testing.T) {
+	pkg := flatPackage(t, [2]string{"Distribution", `<installer-gui-script minSpecVersion="1">
+	<pkg-ref id="com.example.agent"/>
+	<pkg-ref id="com.example.agent" version="3.1" installKBytes="120">#agent.pkg</pkg-ref>
+</installer-gui-script>`})
+
+	metadata, err := read(t, pkg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata != (Metadata{BundleID: "com.example.agent", Version: "3.1"}) {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestReadComponentPackage(t *testing.T) {
+	pkg := flatPackage(t,
+		[2]string{"Scripts/PackageInfo", `<pkg-info identifier="com.example.decoy" version="0.1"/>`},
+		[2]string{"PackageInfo", `<pkg-info format-version="2" identifier="com.example.component" version="1.5" install-location="/"/>`},
+	)
+
+	metadata, err := read(t, pkg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata != (Metadata{BundleID: "com.example.component", Version: "1.5"}) {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+}
+
+func TestReadRejectsUnknownContent(t *testing.T) {
+	for _, content := range []string{"", "pkg", "not an app binary"} {
+		if _, err := read(t, []byte(content)); !errors.Is(err, ErrUnsupported) {
+			t.Fatalf("%q: got %v, want ErrUnsupported", content, err)
+		}
+	}
+}
+
+func TestReadRejectsTruncatedPackage(t *testing.T) {
+	pkg := flatPackage(t, [2]string{"PackageInfo", `<pkg-info identifier="com.example.component" version="1.5"/>`})
+
+	if _, err := read(t, pkg[:len(pkg)-4]); err == nil {
+		t.Fatal("expected an error for a truncated package")
+	}
+}
+
+func TestInspect(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "Tools.ipa")
+	if err := os.WriteFile(binaryPath, archive(t, [2]string{"Payload/Tools.app/Info.plist", xmlPlist("com.example.tools", "1.0")}), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metadata, err := Inspect(binaryPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metadata.BundleID != "com.example.tools" {
+		t.Fatalf("unexpected metadata: %+v", metadata)
+	}
+
+	if _, err := Inspect(filepath.Join(t.TempDir(), "missing.ipa")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got %v, want os.ErrNotExist", err)
+	}
+}
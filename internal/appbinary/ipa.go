@@ -0,0 +1,55 @@
+package appbinary
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// readIPA reads the Info.plist of the app in an .ipa archive.
+func readIPA(r io.ReaderAt, size int64) (Metadata, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("reading ipa: %w", err)
+	}
+
+	for _, file := range archive.File {
+		if !isAppInfoPlist(file.Name) {
+			continue
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return Metadata{}, fmt.Errorf("reading %s: %w", file.Name, err)
+		}
+		data, err := readLimited(content, file.Name)
+		_ = content.Close()
+		if err != nil {
+			return Metadata{}, err
+		}
+
+		values, err := parsePlist(data)
+		if err != nil {
+			return Metadata{}, fmt.Errorf("reading %s: %w", file.Name, err)
+		}
+
+		version := values["CFBundleShortVersionString"]
+		if version == "" {
+			version = values["CFBundleVersion"]
+		}
+
+		return Metadata{BundleID: values["CFBundleIdentifier"], Version: version}, nil
+	}
+
+	return Metadata{}, errors.New("ipa has no Payload/*.app/Info.plist")
+}
+
+// isAppInfoPlist reports whether name is Payload/<name>.app/Info.plist, the
+// Info.plist of the app itself rather than of its extensions or frameworks.
+func isAppInfoPlist(name string) bool {
+	parts := strings.Split(name, "/")
+
+	return len(parts) == 3 && parts[0] == "Payload" && strings.HasSuffix(parts[1], ".app") && parts[2] == "Info.plist"
+}
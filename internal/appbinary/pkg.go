@@ -0,0 +1,185 @@
+package appbinary
+
+import (
+	"compress/bzip2"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+)
+
+const (
+	xarMagic      = "xar!"
+	xarHeaderSize = 28
+)
+
+// xarTOC is the table of contents of a XAR archive, the container format of
+// flat packages.
+type xarTOC struct {
+	Files []xarFile `xml:"toc>file"`
+}
+
+type xarFile struct {
+	Name  string    `xml:"name"`
+	Type  string    `xml:"type"`
+	Data  *xarData  `xml:"data"`
+	Files []xarFile `xml:"file"`
+}
+
+type xarData struct {
+	Offset   int64 `xml:"offset"`
+	Length   int64 `xml:"length"`
+	Encoding struct {
+		Style string `xml:"style,attr"`
+	} `xml:"encoding"`
+}
+
+// distribution holds the parts of a product archive Distribution file that
+// identify the product.
+type distribution struct {
+	Product *struct {
+		ID      string `xml:"id,attr"`
+		Version string `xml:"version,attr"`
+	} `xml:"product"`
+	PkgRefs []struct {
+		ID      string `xml:"id,attr"`
+		Version string `xml:"version,attr"`
+	} `xml:"pkg-ref"`
+}
+
+// packageInfo holds the identifying attributes of a component package.
+type packageInfo struct {
+	Identifier string `xml:"identifier,attr"`
+	Version    string `xml:"version,attr"`
+}
+
+// readPkg reads a flat package. Product archives are identified by their
+// Distribution file, component packages by their PackageInfo.
+func readPkg(r io.ReaderAt, size int64) (Metadata, error) {
+	header := make([]byte, xarHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return Metadata{}, fmt.Errorf("reading package header: %w", err)
+	}
+
+	headerSize := int64(binary.BigEndian.Uint16(header[4:6]))
+	tocLength := binary.BigEndian.Uint64(header[8:16])
+	if headerSize < xarHeaderSize || headerSize > size || tocLength > uint64(size-headerSize) {
+		return Metadata{}, errors.New("package has an invalid header")
+	}
+
+	compressed, err := zlib.NewReader(io.NewSectionReader(r, headerSize, int64(tocLength)))
+	if err != nil {
+		return Metadata{}, fmt.Errorf("reading package table of contents: %w", err)
+	}
+	defer compressed.Close()
+
+	content, err := readLimited(compressed, "package table of contents")
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	var toc xarTOC
+	if err := xml.Unmarshal(content, &toc); err != nil {
+		return Metadata{}, fmt.Errorf("reading package table of contents: %w", err)
+	}
+
+	heap := &xarHeap{r: r, start: headerSize + int64(tocLength), size: size}
+
+	if file := findXARFile(toc.Files, "", func(name string) bool { return name == "Distribution" }); file != nil {
+		data, err := heap.read(file)
+		if err != nil {
+			return Metadata{}, err
+		}
+
+		var dist distribution
+		if err := xml.Unmarshal(data, &dist); err != nil {
+			return Metadata{}, fmt.Errorf("reading Distribution: %w", err)
+		}
+
+		if dist.Product != nil && dist.Product.ID != "" {
+			return Metadata{BundleID: dist.Product.ID, Version: dist.Product.Version}, nil
+		}
+		for _, ref := range dist.PkgRefs {
+			if ref.ID != "" && ref.Version != "" {
+				return Metadata{BundleID: ref.ID, Version: ref.Version}, nil
+			}
+		}
+	}
+
+	// Product archives without a product element fall back to the first
+	// component package they contain.
+	isPackageInfo := func(name string) bool {
+		dir, base := path.Split(name)
+		return base == "PackageInfo" && (dir == "" || path.Ext(path.Clean(dir)) == ".pkg")
+	}
+	if file := findXARFile(toc.Files, "", isPackageInfo); file != nil {
+		data, err := heap.read(file)
+		if err != nil {
+			return Metadata{}, err
+		}
+
+		var info packageInfo
+		if err := xml.Unmarshal(data, &info); err != nil {
+			return Metadata{}, fmt.Errorf("reading PackageInfo: %w", err)
+		}
+
+		return Metadata{BundleID: info.Identifier, Version: info.Version}, nil
+	}
+
+	return Metadata{}, errors.New("package has no Distribution or PackageInfo")
+}
+
+// findXARFile returns the first regular file, depth first, whose path from
+// the archive root satisfies match.
+func findXARFile(files []xarFile, dir string, match func(string) bool) *xarFile {
+	for i := range files {
+		file := &files[i]
+		name := path.Join(dir, file.Name)
+
+		if file.Type == "file" && file.Data != nil && match(name) {
+			return file
+		}
+		if found := findXARFile(file.Files, name, match); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// xarHeap reads file contents from the heap that follows the table of
+// contents.
+type xarHeap struct {
+	r     io.ReaderAt
+	start int64
+	size  int64
+}
+
+func (h *xarHeap) read(file *xarFile) ([]byte, error) {
+	offset := h.start + file.Data.Offset
+	if file.Data.Offset < 0 || file.Data.Length < 0 || offset > h.size || file.Data.Length > h.size-offset {
+		return nil, fmt.Errorf("package file %s is out of range", file.Name)
+	}
+
+	var content io.Reader = io.NewSectionReader(h.r, offset, file.Data.Length)
+	switch file.Data.Encoding.Style {
+	case "", "application/octet-stream":
+	case "application/x-gzip":
+		// XAR labels zlib streams as gzip.
+		decompressed, err := zlib.NewReader(content)
+		if err != nil {
+			return nil, fmt.Errorf("reading package file %s: %w", file.Name, err)
+		}
+		defer decompressed.Close()
+		content = decompressed
+	case "application/x-bzip2":
+		content = bzip2.NewReader(content)
+	default:
+		return nil, fmt.Errorf("package file %s uses unsupported encoding %s", file.Name, file.Data.Encoding.Style)
+	}
+
+	return readLimited(content, file.Name)
+}
@@ -0,0 +1,233 @@
+package appbinary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+)
+
+const (
+	binaryPlistMagic       = "bplist00"
+	binaryPlistTrailerSize = 32
+)
+
+var errNotString = errors.New("plist object is not a string")
+
+// parsePlist returns the string values of the root dictionary of an XML or
+// binary property list. Values of other types are left out.
+func parsePlist(data []byte) (map[string]string, error) {
+	if bytes.HasPrefix(data, []byte(binaryPlistMagic)) {
+		return parseBinaryPlist(data)
+	}
+
+	return parseXMLPlist(data)
+}
+
+func parseXMLPlist(data []byte) (map[string]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	// The root dictionary is the first element inside <plist>.
+	for root := false; ; {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("reading plist: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !root && start.Name.Local == "plist" {
+			root = true
+			continue
+		}
+		if start.Name.Local != "dict" {
+			return nil, errors.New("plist root is not a dictionary")
+		}
+		break
+	}
+
+	values := map[string]string{}
+	key := ""
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("reading plist: %w", err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			switch token.Name.Local {
+			case "key":
+				if err := decoder.DecodeElement(&key, &token); err != nil {
+					return nil, fmt.Errorf("reading plist: %w", err)
+				}
+			case "string":
+				var value string
+				if err := decoder.DecodeElement(&value, &token); err != nil {
+					return nil, fmt.Errorf("reading plist: %w", err)
+				}
+				values[key] = value
+			default:
+				if err := decoder.Skip(); err != nil {
+					return nil, fmt.Errorf("reading plist: %w", err)
+				}
+			}
+		case xml.EndElement:
+			return values, nil
+		}
+	}
+}
+
+// binaryPlist reads objects of a bplist00 property list.
+type binaryPlist struct {
+	data        []byte
+	offsetSize  int
+	refSize     int
+	objects     uint64
+	offsetTable uint64
+}
+
+func parseBinaryPlist(data []byte) (map[string]string, error) {
+	if len(data) < len(binaryPlistMagic)+binaryPlistTrailerSize {
+		return nil, errors.New("binary plist is truncated")
+	}
+
+	trailer := data[len(data)-binaryPlistTrailerSize:]
+	plist := &binaryPlist{
+		data:        data[:len(data)-binaryPlistTrailerSize],
+		offsetSize:  int(trailer[6]),
+		refSize:     int(trailer[7]),
+		objects:     binary.BigEndian.Uint64(trailer[8:16]),
+		offsetTable: binary.BigEndian.Uint64(trailer[24:32]),
+	}
+	top := binary.BigEndian.Uint64(trailer[16:24])
+
+	if plist.offsetSize < 1 || plist.offsetSize > 8 || plist.refSize < 1 || plist.refSize > 8 ||
+		plist.offsetTable > uint64(len(plist.data)) ||
+		plist.objects > (uint64(len(plist.data))-plist.offsetTable)/uint64(plist.offsetSize) ||
+		top >= plist.objects {
+		return nil, errors.New("binary plist has an invalid trailer")
+	}
+
+	offset, err := plist.objectOffset(top)
+	if err != nil {
+		return nil, err
+	}
+	if plist.data[offset]>>4 != 0xD {
+		return nil, errors.New("plist root is not a dictionary")
+	}
+
+	count, start, err := plist.length(offset)
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(len(plist.data)) || start+2*count*uint64(plist.refSize) > uint64(len(plist.data)) {
+		return nil, errors.New("binary plist dictionary is truncated")
+	}
+
+	values := map[string]string{}
+	for i := uint64(0); i < count; i++ {
+		key, err := plist.string(plist.ref(start + i*uint64(plist.refSize)))
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := plist.string(plist.ref(start + (count+i)*uint64(plist.refSize)))
+		if errors.Is(err, errNotString) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+func (p *binaryPlist) ref(at uint64) uint64 {
+	return readUint(p.data[at : at+uint64(p.refSize)])
+}
+
+func (p *binaryPlist) objectOffset(object uint64) (uint64, error) {
+	if object >= p.objects {
+		return 0, errors.New("binary plist references a missing object")
+	}
+
+	at := p.offsetTable + object*uint64(p.offsetSize)
+	offset := readUint(p.data[at : at+uint64(p.offsetSize)])
+	if offset >= uint64(len(p.data)) {
+		return 0, errors.New("binary plist object offset is out of range")
+	}
+
+	return offset, nil
+}
+
+// length returns the element count of the object at offset and where its
+// content starts. Counts of 15 and more follow the marker as an integer.
+func (p *binaryPlist) length(offset uint64) (uint64, uint64, error) {
+	if count := uint64(p.data[offset] & 0x0F); count != 0x0F {
+		return count, offset + 1, nil
+	}
+
+	if offset+2 > uint64(len(p.data)) || p.data[offset+1]>>4 != 0x1 {
+		return 0, 0, errors.New("binary plist has an invalid object length")
+	}
+	size := uint64(1) << (p.data[offset+1] & 0x0F)
+	start := offset + 2
+	if size > 8 || start+size > uint64(len(p.data)) {
+		return 0, 0, errors.New("binary plist has an invalid object length")
+	}
+
+	return readUint(p.data[start : start+size]), start + size, nil
+}
+
+func (p *binaryPlist) string(object uint64) (string, error) {
+	offset, err := p.objectOffset(object)
+	if err != nil {
+		return "", err
+	}
+
+	marker := p.data[offset] >> 4
+	if marker != 0x5 && marker != 0x6 {
+		return "", errNotString
+	}
+
+	count, start, err := p.length(offset)
+	if err != nil {
+		return "", err
+	}
+
+	size := count
+	if marker == 0x6 {
+		size = 2 * count
+	}
+	if count > uint64(len(p.data)) || start+size > uint64(len(p.data)) {
+		return "", errors.New("binary plist string is truncated")
+	}
+	content := p.data[start : start+size]
+
+	// 0x5 marks ASCII strings, 0x6 UTF-16 big endian ones.
+	if marker == 0x5 {
+		return string(content), nil
+	}
+
+	units := make([]uint16, count)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(content[2*i:])
+	}
+
+	return string(utf16.Decode(units)), nil
+}
+
+func readUint(b []byte) uint64 {
+	var value uint64
+	for _, c := range b {
+		value = value<<8 | uint64(c)
+	}
+
+	return value
+}
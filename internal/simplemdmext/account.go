@@ -0,0 +1,59 @@
+package simplemdmext
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+)
+
+// AccountResponse models the payload returned from the SimpleMDM account endpoint.
+type AccountResponse struct {
+	Data AccountData `json:"data"`
+}
+
+// AccountData describes the account the API key belongs to.
+type AccountData struct {
+	Type       string            `json:"type"`
+	ID         jsonNumber        `json:"id"`
+	Attributes AccountAttributes `json:"attributes"`
+}
+
+// AccountAttributes contains the account details exposed by the API.
+type AccountAttributes struct {
+	Name                  string `json:"name"`
+	AppleStoreCountryCode string `json:"apple_store_country_code"`
+	Subscription          struct {
+		Licenses struct {
+			Total     int `json:"total"`
+			Available int `json:"available"`
+		} `json:"licenses"`
+	} `json:"subscription"`
+}
+
+// AccountID returns the account identifier, or an empty string when the API
+// did not report one.
+func (a AccountData) AccountID() string {
+	return a.ID.String()
+}
+
+// GetAccount retrieves the account the client's API key belongs to.
+func GetAccount(ctx context.Context, client *simplemdm.Client) (*AccountResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, APIURL(client, "account"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := Do(client, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp AccountResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,322 @@
+package simplemdmext
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// recordedResponseHeaders lists the response headers kept in a cassette. Other
+// headers, such as cookies, are dropped.
+var recordedResponseHeaders = []string{"Content-Type", "ETag", "Location", "Retry-After", "X-Request-Id"}
+
+// Cassette is a round tripper that records API exchanges to a file or replays
+// them from one, so tests can run without reaching SimpleMDM. Requests are
+// matched on their method, API path, sorted query and a canonical form of
+// their body; repeated identical requests are answered in recording order.
+type Cassette struct {
+	// Env holds values the recording test needs again on replay, such as
+	// the IDs of the fixtures it ran against. It is saved with the cassette.
+	Env map[string]string
+
+	path    string
+	next    http.RoundTripper
+	replay  bool
+	secrets []string
+
+	mu           sync.Mutex
+	interactions []cassetteInteraction
+	pending      map[string][]cassetteResponse
+}
+
+type cassetteFile struct {
+	Env          map[string]string     `json:"env,omitempty"`
+	Interactions []cassetteInteraction `json:"interactions"`
+}
+
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+type cassetteResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+func (r cassetteRequest) key() string {
+	return r.Method + " " + r.Path + "?" + r.Query + "\n" + r.Body
+}
+
+// NewCassette returns a cassette that sends requests through next, or
+// http.DefaultTransport when nil, and records them for Save. Occurrences of
+// secrets, such as the API key, are scrubbed from what is recorded.
+func NewCassette(path string, next http.RoundTripper, secrets ...string) *Cassette {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Cassette{
+		Env:     map[string]string{},
+		path:    path,
+		next:    next,
+		secrets: secrets,
+	}
+}
+
+// LoadCassette reads a recorded cassette for replay. Requests that were not
+// recorded fail without reaching the network. The error wraps
+// os.ErrNotExist when nothing was recorded at path.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+	}
+
+	c := &Cassette{
+		Env:          file.Env,
+		path:         path,
+		replay:       true,
+		interactions: file.Interactions,
+		pending:      map[string][]cassetteResponse{},
+	}
+	if c.Env == nil {
+		c.Env = map[string]string{}
+	}
+	for _, interaction := range file.Interactions {
+		key := interaction.Request.key()
+		c.pending[key] = append(c.pending[key], interaction.Response)
+	}
+
+	return c, nil
+}
+
+// Save writes the recorded exchanges to the cassette file, creating its
+// directory if needed. It does nothing for a replayed cassette.
+func (c *Cassette) Save() error {
+	if c.replay {
+		return nil
+	}
+
+	c.mu.Lock()
+	file := cassetteFile{Env: c.Env, Interactions: c.interactions}
+	data, err := json.MarshalIndent(file, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := cassetteRequest{
+		Method: req.Method,
+		Path:   cassettePath(req.URL.Path),
+		Query:  c.scrub(RedactQuery(canonicalQuery(req.URL.RawQuery))),
+		Body:   c.scrub(canonicalBody(req.Header.Get("Content-Type"), body)),
+	}
+
+	if c.replay {
+		return c.replayResponse(req, recorded)
+	}
+
+	outgoing := req.Clone(req.Context())
+	outgoing.Body = io.NopCloser(bytes.NewReader(body))
+	outgoing.ContentLength = int64(len(body))
+
+	resp, err := c.next.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	response := cassetteResponse{
+		Status: resp.StatusCode,
+		Header: map[string]string{},
+		Body:   c.scrub(string(RedactBody(resp.Header.Get("Content-Type"), responseBody))),
+	}
+	for _, name := range recordedResponseHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			response.Header[name] = value
+		}
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, cassetteInteraction{Request: recorded, Response: response})
+	c.mu.Unlock()
+
+	return resp, nil
+}
+
+func (c *Cassette) replayResponse(req *http.Request, recorded cassetteRequest) (*http.Response, error) {
+	key := recorded.key()
+
+	c.mu.Lock()
+	responses := c.pending[key]
+	if len(responses) == 0 {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("cassette %s has no recorded response for %s %s", c.path, req.Method, recorded.Path)
+	}
+	response := responses[0]
+	c.pending[key] = responses[1:]
+	c.mu.Unlock()
+
+	header := http.Header{}
+	for name, value := range response.Header {
+		header.Set(name, value)
+	}
+
+	return &http.Response{
+		Status:        strconv.Itoa(response.Status) + " " + http.StatusText(response.Status),
+		StatusCode:    response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (c *Cassette) scrub(value string) string {
+	for _, secret := range c.secrets {
+		if secret != "" {
+			value = strings.ReplaceAll(value, secret, RedactedValue)
+		}
+	}
+
+	return value
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	defer req.Body.Close()
+
+	return io.ReadAll(req.Body)
+}
+
+// cassettePath strips the scheme, host and any endpoint prefix from an API
+// path so recordings replay regardless of the endpoint they were made with.
+func cassettePath(path string) string {
+	if i := strings.Index(path, apiPathPrefix+"/"); i >= 0 {
+		return path[i:]
+	}
+
+	return path
+}
+
+func canonicalQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+
+	return values.Encode()
+}
+
+// canonicalBody renders a request body in a form that does not depend on
+// details that vary between runs. Multipart bodies are reduced to their
+// fields and the SHA-256 of their files, dropping the random boundary.
+func canonicalBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		if canonical, err := canonicalMultipart(params["boundary"], body); err == nil {
+			return canonical
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		return string(redactForm([]byte(canonicalQuery(string(body)))))
+	case mediaType == "application/json" || json.Valid(body):
+		return string(redactJSON(body))
+	}
+
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func canonicalMultipart(boundary string, body []byte) (string, error) {
+	if boundary == "" {
+		return "", errors.New("multipart body without boundary")
+	}
+
+	// Parts are sorted because some clients write form fields in map order.
+	var parts []string
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			sort.Strings(parts)
+			return strings.Join(parts, ""), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return "", err
+		}
+
+		name := part.FormName()
+		switch {
+		case part.FileName() != "":
+			sum := sha256.Sum256(content)
+			parts = append(parts, fmt.Sprintf("%s=@%s sha256:%s\n", name, part.FileName(), hex.EncodeToString(sum[:])))
+		case IsSensitiveKey(name):
+			parts = append(parts, fmt.Sprintf("%s=%s\n", name, RedactedValue))
+		default:
+			parts = append(parts, fmt.Sprintf("%s=%s\n", name, content))
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package simplemdmext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+)
+
+func newCassetteClient(t *testing.T, cassette *Cassette, endpoint string) *simplemdm.Client {
+	t.Helper()
+
+	options := DefaultClientOptions()
+	options.Transport = cassette
+	options.RequestsPerSecond = 0
+	if endpoint != "" {
+		parsed, err := ParseEndpoint(endpoint)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		options.Endpoint = parsed
+	}
+
+	client, err := NewClient("a.simplemdm.com", "secret-key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return client
+}
+
+// uploadRequest builds a multipart upload like the app resource does. Every
+// call uses a new random boundary.
+func uploadRequest(t *testing.T, client *simplemdm.Client, content string) *http.Request {
+	t.Helper()
+
+	payload := &bytes.Buffer{}
+	writer := multipart.NewWriter(payload)
+	part, err := writer.CreateFormFile("binary", "app.pkg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = part.Write([]byte(content))
+	_ = writer.WriteField("name", "Uploaded")
+	_ = writer.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, APIURL(client, "apps"), payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
+
+func TestCassetteReplaysRecordedExchanges(t *testing.T) {
+	var uploads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/apps":
+			uploads++
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data":{"id":1,"attributes":{"name":"Uploaded"}}}`))
+		case "/api/v1/devices/7":
+			_, _ = w.Write([]byte(`{"data":{"id":7,"attributes":{"filevault_recovery_key":"ABCD","note":"secret-key"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "Test.json")
+	recorder := NewCassette(path, nil, "secret-key")
+	recorder.Env["SIMPLEMDM_APP_ID"] = "1"
+	client := newCassetteClient(t, recorder, server.URL)
+
+	if _, err := Do(client, uploadRequest(t, client, "binary"), http.StatusCreated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	device, _ := http.NewRequest(http.MethodGet, APIURL(client, "devices/7?include_secret_custom_attributes=true"), nil)
+	if _, err := Do(client, device, http.StatusOK); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(saved), "secret-key") || strings.Contains(string(saved), "ABCD") {
+		t.Fatalf("cassette leaks secrets: %s", saved)
+	}
+
+	player, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if player.Env["SIMPLEMDM_APP_ID"] != "1" {
+		t.Fatalf("unexpected env: %v", player.Env)
+	}
+	client = newCassetteClient(t, player, "")
+
+	body, err := Do(client, uploadRequest(t, client, "binary"), http.StatusCreated)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(body), "Uploaded") {
+		t.Fatalf("unexpected replayed body: %s", body)
+	}
+	if uploads != 1 {
+		t.Fatalf("replay reached the server: %d uploads", uploads)
+	}
+
+	if _, err := Do(client, uploadRequest(t, client, "other binary"), http.StatusCreated); err == nil {
+		t.Fatal("expected a different upload not to match the recording")
+	}
+}
+
+func TestLoadCassetteReportsMissingFile(t *testing.T) {
+	_, err := LoadCassette(filepath.Join(t.TempDir(), "missing.json"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestCanonicalQueryIsSorted(t *testing.T) {
+	if got := canonicalQuery("b=2&a=1"); got != "a=1&b=2" {
+		t.Fatalf("got %q", got)
+	}
+}
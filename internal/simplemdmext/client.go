@@ -0,0 +1,172 @@
+package simplemdmext
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"time"
+	"unsafe"
+
+	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries attempted after the initial request.
+	DefaultMaxRetries = 4
+	// DefaultRetryMinWait is the initial backoff between retries.
+	DefaultRetryMinWait = 1 * time.Second
+	// DefaultRetryMaxWait caps the backoff between retries.
+	DefaultRetryMaxWait = 30 * time.Second
+	// DefaultRequestsPerSecond is the sustained request rate of a client.
+	DefaultRequestsPerSecond = 10
+	// DefaultBurst is the number of requests that may be sent back to back.
+	DefaultBurst = 10
+
+	// defaultAttemptTimeout mirrors the timeout the upstream client applies to
+	// every request. It is enforced per attempt so retries are not cut short.
+	defaultAttemptTimeout = 60 * time.Second
+)
+
+// ClientOptions configures the transport shared by every SimpleMDM request.
+type ClientOptions struct {
+	MaxRetries   int
+	RetryMinWait time.Duration
+	RetryMaxWait time.Duration
+
+	// RequestsPerSecond limits the sustained request rate across every
+	// resource and data source sharing the client. Zero disables limiting.
+	RequestsPerSecond float64
+	Burst             int
+
+	// Endpoint overrides the scheme, host and path prefix of every API
+	// request. When nil, requests go to https://<host>/api/v1.
+	Endpoint *url.URL
+
+	// LogContext is used to log requests issued without a context, such as
+	// those made by the upstream library. Typically the provider's configure
+	// context.
+	LogContext context.Context
+
+	// ReadOnly rejects every request that could modify data, that is any
+	// method other than GET and HEAD, before it leaves the client.
+	ReadOnly bool
+
+	// Transport is the round tripper performing the actual HTTP exchange.
+	// Defaults to http.DefaultTransport when nil.
+	Transport http.RoundTripper
+}
+
+// DefaultClientOptions returns the options used when the provider
+// configuration does not override them.
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		MaxRetries:        DefaultMaxRetries,
+		RetryMinWait:      DefaultRetryMinWait,
+		RetryMaxWait:      DefaultRetryMaxWait,
+		RequestsPerSecond: DefaultRequestsPerSecond,
+		Burst:             DefaultBurst,
+	}
+}
+
+// NewClient builds a SimpleMDM client whose requests, including those issued by
+// the upstream library itself, go through the provider's shared transport.
+// When an endpoint is configured the host argument is ignored in its favour.
+func NewClient(host, apiKey string, opts ClientOptions) (*simplemdm.Client, error) {
+	if opts.Endpoint != nil {
+		host = opts.Endpoint.Host
+	}
+
+	client := simplemdm.NewClient(host, apiKey)
+
+	base := opts.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if opts.Endpoint != nil {
+		base = &endpointTransport{
+			next:     base,
+			host:     host,
+			endpoint: opts.Endpoint,
+		}
+	}
+
+	base = &loggingTransport{
+		next:   base,
+		apiKey: apiKey,
+	}
+
+	if opts.RequestsPerSecond > 0 {
+		base = &rateLimitTransport{
+			next:    base,
+			limiter: newRateLimiter(opts.RequestsPerSecond, opts.Burst),
+		}
+	}
+
+	var transport http.RoundTripper = &retryTransport{
+		next:           base,
+		maxRetries:     opts.MaxRetries,
+		minWait:        opts.RetryMinWait,
+		maxWait:        opts.RetryMaxWait,
+		attemptTimeout: defaultAttemptTimeout,
+	}
+
+	if opts.LogContext != nil {
+		transport = &logContextTransport{
+			next:     transport,
+			fallback: opts.LogContext,
+		}
+	}
+
+	if opts.ReadOnly {
+		transport = &readOnlyTransport{next: transport}
+	}
+
+	httpClient := &http.Client{Transport: transport}
+
+	if err := setHTTPClient(client, httpClient); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// setHTTPClient replaces the unexported http.Client of the upstream client. The
+// library offers no option to inject a transport, so the field is set through
+// reflection. A unit test guards against the field being renamed upstream.
+func setHTTPClient(client *simplemdm.Client, httpClient *http.Client) error {
+	field, ok := httpClientField(client)
+	if !ok {
+		return errors.New("simplemdm client does not expose an http client that can be replaced")
+	}
+
+	field.Set(reflect.ValueOf(httpClient))
+
+	return nil
+}
+
+// httpClientOf returns the http.Client used by the upstream client, which is
+// the shared transport when the client was built with NewClient.
+func httpClientOf(client *simplemdm.Client) *http.Client {
+	field, ok := httpClientField(client)
+	if !ok {
+		return http.DefaultClient
+	}
+
+	if httpClient, _ := field.Interface().(*http.Client); httpClient != nil {
+		return httpClient
+	}
+
+	return http.DefaultClient
+}
+
+func httpClientField(client *simplemdm.Client) (reflect.Value, bool) {
+	field := reflect.ValueOf(client).Elem().FieldByName("httpClient")
+	if !field.IsValid() || field.Type() != reflect.TypeOf((*http.Client)(nil)) {
+		return reflect.Value{}, false
+	}
+
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem(), true
+}
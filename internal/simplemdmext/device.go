@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	simplemdm "github.com/DavidKrau/simplemdm-go-client"
@@ -73,7 +74,7 @@ func (n *jsonNumber) UnmarshalJSON(b []byte) error {
 }
 
 func (n jsonNumber) String() string {
-	if n.raw == "" {
+	if n.raw == "" || n.raw == "null" {
 		return ""
 	}
 
@@ -87,7 +88,7 @@ func (n jsonNumber) String() string {
 
 // GetDevice retrieves a single device record.
 func GetDevice(ctx context.Context, client *simplemdm.Client, deviceID string, includeSecretCustomAttributes bool) (*DeviceResponse, error) {
-	url := fmt.Sprintf("https://%s/api/v1/devices/%s", client.HostName, deviceID)
+	url := APIURL(client, "devices/%s", deviceID)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -99,7 +100,7 @@ func GetDevice(ctx context.Context, client *simplemdm.Client, deviceID string, i
 		req.URL.RawQuery = q.Encode()
 	}
 
-	body, err := client.RequestResponse200(req)
+	body, err := Do(client, req, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -115,49 +116,20 @@ func GetDevice(ctx context.Context, client *simplemdm.Client, deviceID string, i
 // ListDevices retrieves all devices that satisfy the provided filters. It automatically
 // walks through paginated responses.
 func ListDevices(ctx context.Context, client *simplemdm.Client, search string, includeAwaitingEnrollment, includeSecretCustomAttributes bool) ([]DeviceData, error) {
-	results := make([]DeviceData, 0)
-	page := 1
-
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/devices", client.HostName)
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		q := req.URL.Query()
-		q.Set("page", strconv.Itoa(page))
-		if search != "" {
-			q.Set("search", search)
-		}
-		if includeAwaitingEnrollment {
-			q.Set("include_awaiting_enrollment", "true")
-		}
-		if includeSecretCustomAttributes {
-			q.Set("include_secret_custom_attributes", "true")
-		}
-		req.URL.RawQuery = q.Encode()
-
-		body, err := client.RequestResponse200(req)
-		if err != nil {
-			return nil, err
-		}
-
-		var resp DeviceListResponse
-		if err := json.Unmarshal(body, &resp); err != nil {
-			return nil, err
-		}
-
-		results = append(results, resp.Data...)
-
-		if !resp.HasMore || len(resp.Data) == 0 {
-			break
-		}
-
-		page++
+	query := url.Values{}
+	if search != "" {
+		query.Set("search", search)
+	}
+	if includeAwaitingEnrollment {
+		query.Set("include_awaiting_enrollment", "true")
+	}
+	if includeSecretCustomAttributes {
+		query.Set("include_secret_custom_attributes", "true")
 	}
 
-	return results, nil
+	return ListAll(ctx, client, "devices", ListOptions{Query: query, Prefetch: true}, IntCursor(func(device DeviceData) int {
+		return device.ID
+	}))
 }
 
 // ListDeviceProfiles fetches the profiles directly assigned to a device.
@@ -176,42 +148,16 @@ func ListDeviceUsers(ctx context.Context, client *simplemdm.Client, deviceID str
 }
 
 func listRelated(ctx context.Context, client *simplemdm.Client, deviceID, endpoint string) (*DeviceRelatedListResponse, error) {
-	allData := []DeviceRelatedItem{}
-	page := 1
-
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/devices/%s/%s", client.HostName, deviceID, endpoint)
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		q := req.URL.Query()
-		q.Set("page", strconv.Itoa(page))
-		req.URL.RawQuery = q.Encode()
-
-		body, err := client.RequestResponse200(req)
-		if err != nil {
-			return nil, err
-		}
-
-		var resp DeviceRelatedListResponse
-		if err := json.Unmarshal(body, &resp); err != nil {
-			return nil, err
-		}
-
-		allData = append(allData, resp.Data...)
-
-		// Stop if there are no more pages or no data returned
-		if !resp.HasMore || len(resp.Data) == 0 {
-			break
-		}
-
-		page++
+	path := fmt.Sprintf("devices/%s/%s", deviceID, endpoint)
+	data, err := ListAll(ctx, client, path, ListOptions{}, func(item DeviceRelatedItem) string {
+		return item.ID.String()
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &DeviceRelatedListResponse{
-		Data:    allData,
+		Data:    data,
 		HasMore: false,
 	}, nil
 }
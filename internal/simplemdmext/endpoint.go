@@ -0,0 +1,72 @@
+package simplemdmext
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+)
+
+// apiPathPrefix is the path every SimpleMDM API request starts with.
+const apiPathPrefix = "/api/v1"
+
+// APIURL builds the URL of a SimpleMDM API path such as "apps/%s". The URL uses
+// the same https://<host>/api/v1 form as the upstream client so both are
+// mapped onto a custom endpoint by the shared transport.
+func APIURL(client *simplemdm.Client, format string, args ...any) string {
+	return fmt.Sprintf("https://%s%s/", client.HostName, apiPathPrefix) + fmt.Sprintf(format, args...)
+}
+
+// ParseEndpoint validates an endpoint such as http://localhost:8080 or
+// https://proxy.example.com/simplemdm. API paths are appended to it.
+func ParseEndpoint(raw string) (*url.URL, error) {
+	endpoint, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint %q must use the http or https scheme", raw)
+	}
+
+	if endpoint.Host == "" {
+		return nil, fmt.Errorf("endpoint %q must include a host", raw)
+	}
+
+	if endpoint.RawQuery != "" || endpoint.Fragment != "" {
+		return nil, fmt.Errorf("endpoint %q must not include a query or fragment", raw)
+	}
+
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/")
+	endpoint.RawPath = ""
+
+	return endpoint, nil
+}
+
+// endpointTransport redirects API requests addressed to the client host onto
+// the configured endpoint, keeping the scheme and path prefix of the endpoint.
+type endpointTransport struct {
+	next     http.RoundTripper
+	host     string
+	endpoint *url.URL
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host || !strings.HasPrefix(req.URL.Path, apiPathPrefix) {
+		return t.next.RoundTrip(req)
+	}
+
+	rewritten := req.Clone(req.Context())
+	rewritten.URL.Scheme = t.endpoint.Scheme
+	rewritten.URL.Host = t.endpoint.Host
+	rewritten.URL.Path = t.endpoint.Path + req.URL.Path
+	rewritten.URL.RawPath = ""
+	if req.URL.RawPath != "" {
+		rewritten.URL.RawPath = t.endpoint.EscapedPath() + req.URL.RawPath
+	}
+	rewritten.Host = ""
+
+	return t.next.RoundTrip(rewritten)
+}
@@ -0,0 +1,79 @@
+package simplemdmext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		raw      string
+		wantPath string
+		wantErr  bool
+	}{
+		{raw: "http://localhost:8080", wantPath: ""},
+		{raw: "https://proxy.example.com/simplemdm/", wantPath: "/simplemdm"},
+		{raw: "ftp://example.com", wantErr: true},
+		{raw: "localhost:8080", wantErr: true},
+		{raw: "https://example.com?x=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		endpoint, err := ParseEndpoint(tt.raw)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseEndpoint(%q) expected an error", tt.raw)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseEndpoint(%q) unexpected error: %v", tt.raw, err)
+			continue
+		}
+
+		if endpoint.Path != tt.wantPath {
+			t.Errorf("ParseEndpoint(%q) path = %q, want %q", tt.raw, endpoint.Path, tt.wantPath)
+		}
+	}
+}
+
+func TestNewClientSendsRequestsToEndpoint(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{"data":{"id":1}}`))
+	}))
+	defer server.Close()
+
+	endpoint, err := ParseEndpoint(server.URL + "/prefix")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := DefaultClientOptions()
+	options.Endpoint = endpoint
+	client, err := NewClient("ignored.example.com", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.AppGet("1"); err != nil {
+		t.Fatalf("upstream request failed: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, APIURL(client, "devices/%d", 7), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Do(client, req, http.StatusOK); err != nil {
+		t.Fatalf("extension request failed: %v", err)
+	}
+
+	want := []string{"/prefix/api/v1/apps/1", "/prefix/api/v1/devices/7"}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Fatalf("unexpected request paths %v, want %v", paths, want)
+	}
+}
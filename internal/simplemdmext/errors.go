@@ -0,0 +1,150 @@
+package simplemdmext
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// APIError describes a SimpleMDM API response with an unexpected status code.
+type APIError struct {
+	StatusCode int
+	// Code is the machine readable error code reported by SimpleMDM, if any.
+	Code      string
+	Message   string
+	RequestID string
+	Method    string
+	Path      string
+
+	// err is the original error when the APIError was derived from an error
+	// returned by the upstream client.
+	err error
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("SimpleMDM API returned ")
+	b.WriteString(strconv.Itoa(e.StatusCode))
+	if text := http.StatusText(e.StatusCode); text != "" {
+		b.WriteString(" " + text)
+	}
+
+	if e.Method != "" && e.Path != "" {
+		b.WriteString(" for " + e.Method + " " + e.Path)
+	}
+
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	}
+
+	var details []string
+	if e.Code != "" {
+		details = append(details, "code: "+e.Code)
+	}
+	if e.RequestID != "" {
+		details = append(details, "request ID: "+e.RequestID)
+	}
+	if len(details) > 0 {
+		b.WriteString(" (" + strings.Join(details, ", ") + ")")
+	}
+
+	return b.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// NotFound reports whether the API indicated the object does not exist.
+func (e *APIError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// upstreamStatusPattern matches the errors produced by the upstream client,
+// for example "got a non 200 status code: 404 - https://...".
+var upstreamStatusPattern = regexp.MustCompile(`non [0-9 or]+ status code: (\d{3})`)
+
+// AsAPIError extracts an APIError from err. Errors returned by the upstream
+// client carry only a formatted status code; they are converted so callers can
+// treat every API failure the same way.
+func AsAPIError(err error) (*APIError, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	match := upstreamStatusPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return nil, false
+	}
+
+	status, convErr := strconv.Atoi(match[1])
+	if convErr != nil {
+		return nil, false
+	}
+
+	return &APIError{StatusCode: status, Message: err.Error(), err: err}, true
+}
+
+// IsNotFound reports whether err is an API error with a 404 status code.
+func IsNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.NotFound()
+}
+
+// errorResponse models the error payload returned by SimpleMDM.
+type errorResponse struct {
+	Errors []struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+		Code   string `json:"code"`
+	} `json:"errors"`
+}
+
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		Method:     req.Method,
+		Path:       req.URL.Path,
+	}
+
+	var payload errorResponse
+	if err := json.Unmarshal(body, &payload); err == nil && len(payload.Errors) > 0 {
+		messages := make([]string, 0, len(payload.Errors))
+		for _, item := range payload.Errors {
+			if apiErr.Code == "" {
+				apiErr.Code = item.Code
+			}
+
+			switch {
+			case item.Title != "" && item.Detail != "":
+				messages = append(messages, fmt.Sprintf("%s (%s)", item.Title, item.Detail))
+			case item.Title != "":
+				messages = append(messages, item.Title)
+			case item.Detail != "":
+				messages = append(messages, item.Detail)
+			}
+		}
+		apiErr.Message = strings.Join(messages, "; ")
+
+		return apiErr
+	}
+
+	message := strings.TrimSpace(string(RedactBody(resp.Header.Get("Content-Type"), body)))
+	if len(message) > 512 {
+		message = message[:512] + "..."
+	}
+	apiErr.Message = message
+
+	return apiErr
+}
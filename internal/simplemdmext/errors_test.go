@@ -0,0 +1,76 @@
+package simplemdmext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"errors":[{"title":"name can't be blank","code":"invalid"}]}`))
+	}))
+	defer server.Close()
+
+	endpoint, err := ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := DefaultClientOptions()
+	options.Endpoint = endpoint
+	client, err := NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, APIURL(client, "apps"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = Do(client, req, http.StatusCreated)
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Code != "invalid" || apiErr.RequestID != "req-123" {
+		t.Fatalf("unexpected APIError fields: %+v", apiErr)
+	}
+	if apiErr.Path != "/api/v1/apps" || apiErr.Method != http.MethodPost {
+		t.Fatalf("unexpected request details: %s %s", apiErr.Method, apiErr.Path)
+	}
+	if !strings.Contains(apiErr.Error(), "name can't be blank") {
+		t.Fatalf("error message does not include the API message: %s", apiErr.Error())
+	}
+	if apiErr.NotFound() {
+		t.Fatalf("422 must not be reported as not found")
+	}
+}
+
+func TestAsAPIErrorParsesUpstreamErrors(t *testing.T) {
+	upstream := errors.New("got a non 200 status code: 404 - https://a.simplemdm.com/api/v1/apps/1 - ")
+
+	if !IsNotFound(upstream) {
+		t.Fatalf("expected upstream 404 error to be reported as not found")
+	}
+	if !IsNotFound(fmt.Errorf("reading app: %w", &APIError{StatusCode: http.StatusNotFound})) {
+		t.Fatalf("expected wrapped APIError to be reported as not found")
+	}
+	if IsNotFound(errors.New("dial tcp: connection refused")) {
+		t.Fatalf("transport errors must not be reported as not found")
+	}
+
+	apiErr, ok := AsAPIError(upstream)
+	if !ok || apiErr.StatusCode != http.StatusNotFound || !errors.Is(apiErr, upstream) {
+		t.Fatalf("unexpected conversion result: %+v, %v", apiErr, ok)
+	}
+}
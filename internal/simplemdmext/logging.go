@@ -0,0 +1,172 @@
+package simplemdmext
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// HTTPLogSubsystem is the tflog subsystem used for API request logs. Its
+	// level follows TF_LOG unless TF_LOG_PROVIDER_SIMPLEMDM_HTTP is set.
+	HTTPLogSubsystem = "http"
+	httpLogLevelEnv  = "TF_LOG_PROVIDER_SIMPLEMDM_HTTP"
+
+	// maxLoggedBodyBytes bounds the size of bodies written to the log.
+	maxLoggedBodyBytes = 4 << 10
+	// maxCapturedBodyBytes bounds how much of a request or response body is
+	// read for logging so large uploads and downloads are never buffered.
+	maxCapturedBodyBytes = 64 << 10
+)
+
+type attemptContextKey struct{}
+
+type logContextKey struct{}
+
+// attemptFromContext returns the 1-based attempt number set by the retry
+// transport.
+func attemptFromContext(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptContextKey{}).(int); ok {
+		return attempt
+	}
+
+	return 1
+}
+
+// logContextTransport attaches the provider's configure context to requests
+// built without one. The upstream library creates its requests with
+// http.NewRequest, which would otherwise leave them without a logger.
+type logContextTransport struct {
+	next     http.RoundTripper
+	fallback context.Context
+}
+
+func (t *logContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context().Done() == nil {
+		req = req.WithContext(context.WithValue(req.Context(), logContextKey{}, t.fallback))
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// loggingTransport writes one debug entry per API attempt with the method,
+// path, status, latency and redacted, truncated bodies. Entries inherit the
+// fields of the calling resource or data source, such as tf_resource_type.
+type loggingTransport struct {
+	next   http.RoundTripper
+	apiKey string
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if fallback, ok := ctx.Value(logContextKey{}).(context.Context); ok {
+		ctx = fallback
+	}
+
+	ctx = tflog.NewSubsystem(ctx, HTTPLogSubsystem, tflog.WithLevelFromEnv(httpLogLevelEnv), tflog.WithRootFields())
+	if t.apiKey != "" {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, HTTPLogSubsystem, t.apiKey)
+		ctx = tflog.SubsystemMaskMessageStrings(ctx, HTTPLogSubsystem, t.apiKey)
+	}
+
+	fields := map[string]any{
+		"http_method":  req.Method,
+		"http_path":    req.URL.Path,
+		"http_attempt": attemptFromContext(req.Context()),
+	}
+	if req.URL.RawQuery != "" {
+		fields["http_query"] = RedactQuery(req.URL.RawQuery)
+	}
+	if body := requestBodyForLog(req); body != "" {
+		fields["http_request_body"] = body
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields["http_duration_ms"] = time.Since(start).Milliseconds()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, HTTPLogSubsystem, "SimpleMDM API request failed", fields)
+		return nil, err
+	}
+
+	fields["http_status"] = resp.StatusCode
+	if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
+		fields["http_request_id"] = requestID
+	}
+
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxCapturedBodyBytes+1))
+	if readErr != nil {
+		_ = resp.Body.Close()
+		fields["error"] = readErr.Error()
+		tflog.SubsystemDebug(ctx, HTTPLogSubsystem, "SimpleMDM API response could not be read", fields)
+		return nil, readErr
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+	if len(body) > 0 {
+		truncated := len(body) > maxCapturedBodyBytes
+		if truncated {
+			body = body[:maxCapturedBodyBytes]
+		}
+		fields["http_response_body"] = bodyForLog(resp.Header.Get("Content-Type"), body, truncated)
+	}
+
+	tflog.SubsystemDebug(ctx, HTTPLogSubsystem, "SimpleMDM API request", fields)
+
+	return resp, nil
+}
+
+// requestBodyForLog reads a copy of the request body without consuming it.
+func requestBodyForLog(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return ""
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/") {
+		return string(RedactBody(contentType, []byte{0}))
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	captured, err := io.ReadAll(io.LimitReader(body, maxCapturedBodyBytes+1))
+	if err != nil {
+		return ""
+	}
+
+	truncated := len(captured) > maxCapturedBodyBytes
+	if truncated {
+		captured = captured[:maxCapturedBodyBytes]
+	}
+
+	return bodyForLog(contentType, captured, truncated)
+}
+
+// bodyForLog redacts a body before truncating it for the log.
+func bodyForLog(contentType string, body []byte, truncated bool) string {
+	redacted := RedactBody(contentType, body)
+	if len(redacted) > maxLoggedBodyBytes {
+		redacted = redacted[:maxLoggedBodyBytes]
+		truncated = true
+	}
+
+	if truncated {
+		return string(redacted) + "...[truncated]"
+	}
+
+	return string(redacted)
+}
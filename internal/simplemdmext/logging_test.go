@@ -0,0 +1,126 @@
+package simplemdmext
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestLoggingTransportLogsRedactedExchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"data":{"attributes":{"filevault_recovery_key":"KEY-MATERIAL"}}}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	ctx = tflog.SetField(ctx, "tf_resource_type", "simplemdm_device_command")
+
+	client := &http.Client{Transport: &loggingTransport{next: http.DefaultTransport, apiKey: "api-key-value"}}
+
+	form := url.Values{"new_password": {"Sup3rSecret"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/api/v1/devices/1/set_admin_password", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("unable to decode logs: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d: %v", len(entries), entries)
+	}
+
+	entry := entries[0]
+	expected := map[string]any{
+		"http_method":      "POST",
+		"http_path":        "/api/v1/devices/1/set_admin_password",
+		"http_status":      float64(http.StatusAccepted),
+		"http_attempt":     float64(1),
+		"http_request_id":  "req-123",
+		"tf_resource_type": "simplemdm_device_command",
+	}
+	for key, want := range expected {
+		if entry[key] != want {
+			t.Errorf("expected %s=%v, got %v", key, want, entry[key])
+		}
+	}
+
+	if _, ok := entry["http_duration_ms"]; !ok {
+		t.Errorf("expected the request latency to be logged")
+	}
+
+	logged := output.String()
+	if strings.Contains(logged, "Sup3rSecret") || strings.Contains(logged, "KEY-MATERIAL") {
+		t.Fatalf("secrets leaked into logs: %s", logged)
+	}
+}
+
+func TestLoggingTransportStreamsLargeResponses(t *testing.T) {
+	payload := strings.Repeat("x", 2*maxCapturedBodyBytes)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client := &http.Client{Transport: &loggingTransport{next: http.DefaultTransport}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/apps/1/binary", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != payload {
+		t.Fatalf("expected the whole response body, got %d bytes", len(body))
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("unable to decode logs: %v", err)
+	}
+	if len(entries) != 1 || !strings.HasSuffix(entries[0]["http_response_body"].(string), "...[truncated]") {
+		t.Fatalf("expected a truncated response body in the log, got %v", entries)
+	}
+}
+
+func TestBodyForLogTruncates(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), maxLoggedBodyBytes*2)
+
+	logged := bodyForLog("text/plain", body, false)
+
+	if !strings.HasSuffix(logged, "...[truncated]") || len(logged) > maxLoggedBodyBytes+len("...[truncated]") {
+		t.Fatalf("expected body to be truncated, got %d bytes", len(logged))
+	}
+}
@@ -0,0 +1,218 @@
+package simplemdmext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+)
+
+// DefaultPageLimit is the largest page size accepted by the SimpleMDM API.
+const DefaultPageLimit = 100
+
+// ListOptions configures how a collection endpoint is walked.
+type ListOptions struct {
+	// Limit is the number of records requested per page. Zero selects
+	// DefaultPageLimit.
+	Limit int
+	// Query holds additional parameters sent with every page request.
+	Query url.Values
+	// Prefetch requests the next page while the records of the current page
+	// are being consumed. SimpleMDM paginates with a cursor taken from the last
+	// record of a page, so pages cannot be requested fully in parallel; this is
+	// the concurrency the API allows.
+	Prefetch bool
+}
+
+// CursorFunc returns the value passed as starting_after to continue listing
+// after the given record, usually its ID.
+type CursorFunc[T any] func(T) string
+
+// IntCursor is a CursorFunc for records identified by an integer ID.
+func IntCursor[T any](id func(T) int) CursorFunc[T] {
+	return func(item T) string {
+		return strconv.Itoa(id(item))
+	}
+}
+
+// listPage models a single page of a SimpleMDM collection response.
+type listPage[T any] struct {
+	Data    []T  `json:"data"`
+	HasMore bool `json:"has_more"`
+}
+
+// Paginate iterates over every record of the collection at path, relative to
+// the API base path, following starting_after cursors while has_more is set.
+// Iteration stops at the first error, which is yielded with a zero record, or
+// when ctx is cancelled.
+func Paginate[T any](ctx context.Context, client *simplemdm.Client, path string, opts ListOptions, cursor CursorFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var pages iter.Seq2[listPage[T], error]
+		if opts.Prefetch {
+			pages = prefetchPages(ctx, client, path, opts, cursor)
+		} else {
+			pages = fetchPages(ctx, client, path, opts, cursor)
+		}
+
+		for page, err := range pages {
+			if err == nil {
+				// A prefetched page may arrive after the caller cancelled.
+				err = ctx.Err()
+			}
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Data {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ListAll collects every record of the collection at path.
+func ListAll[T any](ctx context.Context, client *simplemdm.Client, path string, opts ListOptions, cursor CursorFunc[T]) ([]T, error) {
+	results := make([]T, 0)
+	for item, err := range Paginate(ctx, client, path, opts, cursor) {
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, item)
+	}
+
+	return results, nil
+}
+
+// fetchPages requests pages one after another as the caller consumes them.
+func fetchPages[T any](ctx context.Context, client *simplemdm.Client, path string, opts ListOptions, cursor CursorFunc[T]) iter.Seq2[listPage[T], error] {
+	return func(yield func(listPage[T], error) bool) {
+		startingAfter := ""
+		for {
+			page, err := fetchPage[T](ctx, client, path, opts, startingAfter)
+			if err != nil {
+				yield(listPage[T]{}, err)
+				return
+			}
+
+			next, more, err := nextCursor(page, cursor, startingAfter)
+			if !yield(page, nil) {
+				return
+			}
+			if err != nil {
+				yield(listPage[T]{}, err)
+				return
+			}
+			if !more {
+				return
+			}
+
+			startingAfter = next
+		}
+	}
+}
+
+// prefetchPages requests pages from a background goroutine that stays one
+// page ahead of the caller. The goroutine exits once ctx is cancelled.
+func prefetchPages[T any](ctx context.Context, client *simplemdm.Client, path string, opts ListOptions, cursor CursorFunc[T]) iter.Seq2[listPage[T], error] {
+	type result struct {
+		page listPage[T]
+		err  error
+	}
+
+	return func(yield func(listPage[T], error) bool) {
+		results := make(chan result, 1)
+		// complete is only read after results is closed.
+		complete := false
+
+		go func() {
+			defer close(results)
+
+			for page, err := range fetchPages(ctx, client, path, opts, cursor) {
+				select {
+				case results <- result{page: page, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			complete = true
+		}()
+
+		for r := range results {
+			if !yield(r.page, r.err) || r.err != nil {
+				return
+			}
+		}
+
+		if !complete {
+			yield(listPage[T]{}, ctx.Err())
+		}
+	}
+}
+
+func fetchPage[T any](ctx context.Context, client *simplemdm.Client, path string, opts ListOptions, startingAfter string) (listPage[T], error) {
+	if err := ctx.Err(); err != nil {
+		return listPage[T]{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, APIURL(client, "%s", path), nil)
+	if err != nil {
+		return listPage[T]{}, err
+	}
+
+	q := req.URL.Query()
+	for key, values := range opts.Query {
+		for _, value := range values {
+			q.Add(key, value)
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	q.Set("limit", strconv.Itoa(limit))
+	if startingAfter != "" {
+		q.Set("starting_after", startingAfter)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	body, err := Do(client, req, http.StatusOK)
+	if err != nil {
+		return listPage[T]{}, err
+	}
+
+	var page listPage[T]
+	if err := json.Unmarshal(body, &page); err != nil {
+		return listPage[T]{}, fmt.Errorf("decoding %s page: %w", path, err)
+	}
+
+	return page, nil
+}
+
+// nextCursor returns the starting_after value for the page following page and
+// whether one should be requested. A cursor that does not advance is reported
+// as an error instead of looping forever.
+func nextCursor[T any](page listPage[T], cursor CursorFunc[T], previous string) (string, bool, error) {
+	if !page.HasMore || len(page.Data) == 0 {
+		return "", false, nil
+	}
+
+	next := cursor(page.Data[len(page.Data)-1])
+	if next == "" || next == previous {
+		return "", false, fmt.Errorf("pagination cursor did not advance past %q", previous)
+	}
+
+	return next, true, nil
+}
@@ -0,0 +1,146 @@
+package simplemdmext
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+)
+
+type testRecord struct {
+	ID int `json:"id"`
+}
+
+var testRecordCursor = IntCursor(func(r testRecord) int { return r.ID })
+
+// newPaginatedServer serves total records with IDs 1..total using cursor
+// pagination and counts the requests it receives.
+func newPaginatedServer(t *testing.T, total int, requests *atomic.Int32) *simplemdm.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if r.URL.Query().Get("search") != "mac" {
+			t.Errorf("missing search filter in %s", r.URL.RawQuery)
+		}
+
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		after, _ := strconv.Atoi(r.URL.Query().Get("starting_after"))
+
+		var page listPage[testRecord]
+		for id := after + 1; id <= total && len(page.Data) < limit; id++ {
+			page.Data = append(page.Data, testRecord{ID: id})
+		}
+		page.HasMore = after+len(page.Data) < total
+
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+
+	endpoint, err := ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := DefaultClientOptions()
+	options.Endpoint = endpoint
+	client, err := NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return client
+}
+
+func TestListAllFollowsCursor(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		var requests atomic.Int32
+		client := newPaginatedServer(t, 250, &requests)
+
+		opts := ListOptions{Query: url.Values{"search": {"mac"}}, Prefetch: prefetch}
+		records, err := ListAll(context.Background(), client, "devices", opts, testRecordCursor)
+		if err != nil {
+			t.Fatalf("prefetch=%v: unexpected error: %v", prefetch, err)
+		}
+
+		if len(records) != 250 {
+			t.Fatalf("prefetch=%v: got %d records, want 250", prefetch, len(records))
+		}
+		for i, record := range records {
+			if record.ID != i+1 {
+				t.Fatalf("prefetch=%v: record %d has ID %d", prefetch, i, record.ID)
+			}
+		}
+
+		if got := requests.Load(); got != 3 {
+			t.Fatalf("prefetch=%v: made %d requests, want 3", prefetch, got)
+		}
+	}
+}
+
+func TestPaginateStopsWhenConsumerStops(t *testing.T) {
+	var requests atomic.Int32
+	client := newPaginatedServer(t, 1000, &requests)
+
+	opts := ListOptions{Limit: 10, Query: url.Values{"search": {"mac"}}}
+	for record, err := range Paginate(context.Background(), client, "devices", opts, testRecordCursor) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if record.ID == 15 {
+			break
+		}
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("made %d requests, want 2", got)
+	}
+}
+
+func TestPaginateHonoursCancellation(t *testing.T) {
+	var requests atomic.Int32
+	client := newPaginatedServer(t, 1000, &requests)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opts := ListOptions{Limit: 10, Query: url.Values{"search": {"mac"}}, Prefetch: true}
+
+	var gotErr error
+	for record, err := range Paginate(ctx, client, "devices", opts, testRecordCursor) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		if record.ID == 5 {
+			cancel()
+		}
+	}
+
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", gotErr)
+	}
+	if got := requests.Load(); got > 3 {
+		t.Fatalf("made %d requests after cancellation", got)
+	}
+}
+
+func TestNextCursorRejectsStalledCursor(t *testing.T) {
+	page := listPage[testRecord]{Data: []testRecord{{ID: 3}}, HasMore: true}
+
+	if _, _, err := nextCursor(page, testRecordCursor, "3"); err == nil {
+		t.Fatalf("expected an error for a cursor that does not advance")
+	}
+
+	next, more, err := nextCursor(page, testRecordCursor, "1")
+	if err != nil || !more || next != "3" {
+		t.Fatalf("unexpected cursor result %q, %v, %v", next, more, err)
+	}
+}
@@ -0,0 +1,40 @@
+package simplemdmext
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+)
+
+// ErrReadOnly is returned for requests that would modify data through a
+// read-only client.
+var ErrReadOnly = errors.New("the SimpleMDM client is read-only")
+
+// readOnlyTransport is the outermost transport of a read-only client. It
+// rejects mutating requests before they are retried, rate limited or sent.
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: refusing %s %s", ErrReadOnly, req.Method, req.URL.Path)
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// IsReadOnly reports whether client was built with ClientOptions.ReadOnly.
+func IsReadOnly(client *simplemdm.Client) bool {
+	if client == nil {
+		return false
+	}
+
+	_, ok := httpClientOf(client).Transport.(*readOnlyTransport)
+	return ok
+}
@@ -0,0 +1,65 @@
+package simplemdmext
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadOnlyClientRejectsMutations(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	endpoint, err := ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := DefaultClientOptions()
+	options.Endpoint = endpoint
+	options.ReadOnly = true
+	client, err := NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !IsReadOnly(client) {
+		t.Fatalf("expected the client to be read-only")
+	}
+
+	get, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, APIURL(client, "apps"), nil)
+	if _, err := Do(client, get, http.StatusOK); err != nil {
+		t.Fatalf("GET must be allowed: %v", err)
+	}
+
+	post, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, APIURL(client, "apps"), strings.NewReader("name=x"))
+	if _, err := Do(client, post, http.StatusCreated); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly for POST, got %v", err)
+	}
+
+	if err := client.AppDelete("1"); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly for an upstream DELETE, got %v", err)
+	}
+
+	if len(methods) != 1 || methods[0] != http.MethodGet {
+		t.Fatalf("only the GET request should reach the server, got %v", methods)
+	}
+}
+
+func TestIsReadOnlyDefaultsToFalse(t *testing.T) {
+	client, err := NewClient("example.com", "key", DefaultClientOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if IsReadOnly(client) || IsReadOnly(nil) {
+		t.Fatalf("clients are writable unless ReadOnly is set")
+	}
+}
@@ -0,0 +1,148 @@
+package simplemdmext
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces secrets in logged payloads.
+const RedactedValue = "REDACTED"
+
+// sensitiveKeys lists payload keys whose values must never be logged: admin
+// and lock passwords sent by device commands, and the recovery material
+// returned by the device endpoints.
+var sensitiveKeys = map[string]struct{}{
+	"activation_lock_bypass_code": {},
+	"admin_password":              {},
+	"apikey":                      {},
+	"api_key":                     {},
+	"filevault_recovery_key":      {},
+	"firmware_password":           {},
+	"new_password":                {},
+	"password":                    {},
+	"personal_recovery_key":       {},
+	"pin":                         {},
+	"recovery_key":                {},
+	"recovery_lock_password":      {},
+}
+
+// jsonSecretPattern matches sensitive string values in JSON that could not be
+// parsed, for example a body cut off by a size limit.
+var (
+	jsonSecretPattern      = regexp.MustCompile(`("(?:(?:[A-Za-z_]*_)?(?:password|recovery_key)|pin|apikey|api_key|activation_lock_bypass_code)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	jsonSecretValueBefore  = regexp.MustCompile(`("secret"\s*:\s*true\s*,\s*"value"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	jsonSecretValueAfter   = regexp.MustCompile(`("value"\s*:\s*)"(?:[^"\\]|\\.)*"(\s*,\s*"secret"\s*:\s*true)`)
+	redactedJSONString     = []byte(`${1}"` + RedactedValue + `"`)
+	redactedJSONStringPair = []byte(`${1}"` + RedactedValue + `"${2}`)
+)
+
+// IsSensitiveKey reports whether values stored under key must be redacted.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := sensitiveKeys[key]; ok {
+		return true
+	}
+
+	return strings.HasSuffix(key, "_password") || strings.HasSuffix(key, "_recovery_key")
+}
+
+// RedactBody returns a copy of an HTTP body that is safe to log. JSON and form
+// encoded payloads have their secrets replaced; multipart payloads, which carry
+// app binaries, are omitted entirely.
+func RedactBody(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return []byte("[multipart body omitted]")
+	case mediaType == "application/x-www-form-urlencoded":
+		return redactForm(body)
+	default:
+		return redactJSON(body)
+	}
+}
+
+// RedactQuery returns a copy of an encoded query string with secrets replaced.
+func RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	return string(redactForm([]byte(rawQuery)))
+}
+
+func redactForm(body []byte) []byte {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return []byte("[unparseable form body omitted]")
+	}
+
+	for key := range values {
+		if IsSensitiveKey(key) {
+			values[key] = []string{RedactedValue}
+		}
+	}
+
+	return []byte(values.Encode())
+}
+
+func redactJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var payload any
+	if err := decoder.Decode(&payload); err != nil {
+		return redactUnparsedJSON(body)
+	}
+
+	redacted, err := json.Marshal(redactValue(payload))
+	if err != nil {
+		return redactUnparsedJSON(body)
+	}
+
+	return redacted
+}
+
+func redactUnparsedJSON(body []byte) []byte {
+	body = jsonSecretPattern.ReplaceAll(body, redactedJSONString)
+	body = jsonSecretValueBefore.ReplaceAll(body, redactedJSONString)
+	return jsonSecretValueAfter.ReplaceAll(body, redactedJSONStringPair)
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		// Custom attribute values flagged as secret are returned when
+		// include_secret_custom_attributes is requested.
+		if secret, ok := v["secret"].(bool); ok && secret {
+			if _, ok := v["value"]; ok {
+				v["value"] = RedactedValue
+			}
+		}
+
+		for key, item := range v {
+			if item != nil && IsSensitiveKey(key) {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,72 @@
+package simplemdmext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactBodyJSON(t *testing.T) {
+	body := `{"data":{"attributes":{"name":"Laptop","filevault_recovery_key":"AAAA-BBBB","recovery_lock_password":"hunter2"},` +
+		`"relationships":{"custom_attribute_values":{"data":[` +
+		`{"id":"token","attributes":{"secret":true,"value":"s3cr3t"}},` +
+		`{"id":"team","attributes":{"secret":false,"value":"platform"}}]}}}}`
+
+	redacted := string(RedactBody("application/json", []byte(body)))
+
+	for _, secret := range []string{"AAAA-BBBB", "hunter2", "s3cr3t"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("expected %q to be redacted from %s", secret, redacted)
+		}
+	}
+
+	for _, kept := range []string{"Laptop", "platform"} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("expected %q to be kept in %s", kept, redacted)
+		}
+	}
+}
+
+func TestRedactBodyTruncatedJSON(t *testing.T) {
+	body := `{"data":{"attributes":{"firmware_password":"abc","secret":true,"value":"xyz","name":"Lap`
+
+	redacted := string(RedactBody("application/json", []byte(body)))
+
+	if strings.Contains(redacted, `"abc"`) || strings.Contains(redacted, `"xyz"`) {
+		t.Fatalf("expected secrets to be redacted from %s", redacted)
+	}
+}
+
+func TestRedactBodyForm(t *testing.T) {
+	body := "new_password=Sup3rSecret&account=admin"
+
+	redacted := string(RedactBody("application/x-www-form-urlencoded", []byte(body)))
+
+	if strings.Contains(redacted, "Sup3rSecret") {
+		t.Fatalf("expected admin password to be redacted from %s", redacted)
+	}
+	if !strings.Contains(redacted, "account=admin") {
+		t.Fatalf("expected non-secret fields to be kept in %s", redacted)
+	}
+}
+
+func TestRedactBodyMultipart(t *testing.T) {
+	redacted := string(RedactBody("multipart/form-data; boundary=abc", []byte("--abc\r\nbinary")))
+
+	if strings.Contains(redacted, "binary") {
+		t.Fatalf("expected multipart payload to be omitted, got %s", redacted)
+	}
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	for _, key := range []string{"new_password", "FileVault_Recovery_Key", "pin", "custom_admin_password"} {
+		if !IsSensitiveKey(key) {
+			t.Errorf("expected %q to be sensitive", key)
+		}
+	}
+
+	for _, key := range []string{"name", "secret", "value", "serial_number"} {
+		if IsSensitiveKey(key) {
+			t.Errorf("expected %q not to be sensitive", key)
+		}
+	}
+}
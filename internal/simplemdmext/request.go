@@ -0,0 +1,33 @@
+package simplemdmext
+
+import (
+	"io"
+	"net/http"
+	"slices"
+
+	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+)
+
+// Do sends an authenticated request through the client's shared transport and
+// returns the response body. Responses whose status is not one of expected
+// are returned as an *APIError.
+func Do(client *simplemdm.Client, req *http.Request, expected ...int) ([]byte, error) {
+	req.SetBasicAuth(client.APIKey, "")
+
+	resp, err := httpClientOf(client).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(expected, resp.StatusCode) {
+		return nil, newAPIError(req, resp, body)
+	}
+
+	return body, nil
+}
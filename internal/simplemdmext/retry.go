@@ -0,0 +1,200 @@
+package simplemdmext
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryTransport retries requests that failed because of rate limiting or
+// transient server errors. Rate limited requests are retried for every method
+// since SimpleMDM rejects them before processing; transport failures and 5xx
+// responses are only retried for idempotent methods.
+type retryTransport struct {
+	next           http.RoundTripper
+	maxRetries     int
+	minWait        time.Duration
+	maxWait        time.Duration
+	attemptTimeout time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		attemptReq, cancel, err := t.newAttempt(req, attempt)
+		if err != nil {
+			closeRequestBody(req)
+			return nil, err
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if attempt >= t.maxRetries || !shouldRetry(req, resp, err) {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+		}
+		cancel()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			closeRequestBody(req)
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// closeRequestBody closes the body of a request the transport gives up on, as
+// the http.RoundTripper contract requires even when an error is returned.
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
+
+type noAttemptTimeoutKey struct{}
+
+// WithoutAttemptTimeout marks requests made with ctx as exempt from the
+// per-attempt timeout, leaving them bounded by ctx alone. Streamed uploads
+// use it, since a large binary can take longer than any fixed timeout.
+func WithoutAttemptTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noAttemptTimeoutKey{}, true)
+}
+
+// newAttempt clones the request for a single attempt, rewinding the body for
+// retries and bounding the attempt with its own timeout unless the request
+// opted out through WithoutAttemptTimeout.
+func (t *retryTransport) newAttempt(req *http.Request, attempt int) (*http.Request, context.CancelFunc, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if exempt, _ := req.Context().Value(noAttemptTimeoutKey{}).(bool); t.attemptTimeout > 0 && !exempt {
+		ctx, cancel = context.WithTimeout(req.Context(), t.attemptTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+
+	ctx = context.WithValue(ctx, attemptContextKey{}, attempt+1)
+
+	attemptReq := req.Clone(ctx)
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	return attemptReq, cancel, nil
+}
+
+// backoff returns the delay before the next attempt. A Retry-After header
+// sent by the API takes precedence over the jittered exponential schedule,
+// but never exceeds maxWait. A zero minWait retries without waiting.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(wait, t.maxWait)
+		}
+	}
+
+	if t.minWait <= 0 {
+		return 0
+	}
+
+	wait := t.maxWait
+	if attempt < 32 {
+		if scaled := t.minWait << attempt; scaled > 0 && scaled < t.maxWait {
+			wait = scaled
+		}
+	}
+
+	half := wait / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	// Bodies that cannot be replayed must not be sent twice.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotent(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	default:
+		return false
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter interprets a Retry-After header expressed either in seconds
+// or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// cancelOnClose releases the attempt context once the caller is done with the
+// response body.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,23 @@
+package simplemdmfake
+
+import "net/http"
+
+// accountLicenses is the number of device licenses of the fake account.
+const accountLicenses = 100
+
+func (s *Server) registerAccount() {
+	s.handle(http.MethodGet, "/account", s.getAccount)
+}
+
+func (s *Server) getAccount(w http.ResponseWriter, _ *http.Request) {
+	writeObject(w, http.StatusOK, object("account", 1, map[string]any{
+		"name":                     "SimpleMDM Fake",
+		"apple_store_country_code": "US",
+		"subscription": map[string]any{
+			"licenses": map[string]any{
+				"total":     accountLicenses,
+				"available": max(accountLicenses-len(s.tables[tableDevices]), 0),
+			},
+		},
+	}, nil))
+}
@@ -0,0 +1,362 @@
+package simplemdmfake
+
+import (
+	"bytes"
+	"hash/fnv"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/appbinary"
+)
+
+var (
+	appDeployTargets      = []string{"none", "outdated", "all"}
+	appInstallerTypes     = []string{"package", "copy_from_dmg", "nopkg"}
+	appUninstallMethods   = []string{"removepackages", "remove_copied_items", "remove_app", "uninstall_script"}
+	appMunkiSettings      = []string{"installer_type", "preinstall_script", "postinstall_script", "uninstall_method", "uninstall_script", "minimum_os_version"}
+	appMinimumOSVersionRE = regexp.MustCompile(`^\d+(\.\d+){0,2}$`)
+	managedConfigTypes    = []string{"boolean", "date", "float", "float array", "integer", "integer array", "string", "string array"}
+)
+
+func (s *Server) registerApps() {
+	s.handle(http.MethodGet, "/apps", s.listApps)
+	s.handle(http.MethodPost, "/apps", s.createApp)
+	s.handle(http.MethodGet, "/apps/{id}", s.getApp)
+	s.handle(http.MethodPatch, "/apps/{id}", s.updateApp)
+	s.handle(http.MethodDelete, "/apps/{id}", s.deleteApp)
+
+	s.handle(http.MethodGet, "/apps/{id}/managed_configs", s.listManagedConfigs)
+	s.handle(http.MethodPost, "/apps/{id}/managed_configs", s.createManagedConfig)
+	s.handle(http.MethodPost, "/apps/{id}/managed_configs/push", s.pushManagedConfigs)
+	s.handle(http.MethodDelete, "/apps/{id}/managed_configs/{config}", s.deleteManagedConfig)
+}
+
+func (s *Server) renderApp(rec *record) map[string]any {
+	return object("app", rec.id, rec.attributes, nil)
+}
+
+func (s *Server) listApps(w http.ResponseWriter, r *http.Request) {
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableApps) {
+		objects = append(objects, s.renderApp(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) getApp(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableApps, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, s.renderApp(rec))
+
+	// Uploaded binaries finish processing once they have been looked up.
+	if rec.attributes["processing_status"] == "processing" {
+		rec.attributes["processing_status"] = "processed"
+	}
+}
+
+// createApp adds an App Store app by app_store_id or bundle_id, or an
+// enterprise app from an uploaded binary. Uploaded apps take the bundle
+// identifier and version found in the binary, and are reported as processing
+// until the next time they are looked up.
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request) {
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	binary, hasBinary := p.files["binary"]
+	storeID, bundleID, name := p.get("app_store_id"), p.get("bundle_id"), p.get("name")
+
+	attributes := map[string]any{
+		"app_type":              "app store",
+		"bundle_identifier":     bundleID,
+		"itunes_store_id":       nil,
+		"version":               "1.0",
+		"platform_support":      "iOS",
+		"processing_status":     "processed",
+		"installation_channels": []string{"standard"},
+		"deploy_to":             "none",
+		"status":                "available",
+	}
+
+	switch {
+	case hasBinary:
+		attributes["app_type"] = "enterprise"
+		attributes["platform_support"] = "macOS"
+		attributes["installation_channels"] = []string{"standard", "self_serve"}
+		attributes["processing_status"] = "processing"
+		attributes["installer_type"] = "package"
+		attributes["uninstall_method"] = "removepackages"
+		if metadata, err := appbinary.Read(bytes.NewReader(binary), int64(len(binary))); err == nil {
+			attributes["bundle_identifier"] = metadata.BundleID
+			if metadata.Version != "" {
+				attributes["version"] = metadata.Version
+			}
+		} else if bundleID == "" {
+			attributes["bundle_identifier"] = "com.example.upload" + strconv.Itoa(len(binary))
+		}
+	case storeID != "":
+		id, err := strconv.Atoi(storeID)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "app_store_id is invalid")
+			return
+		}
+		attributes["itunes_store_id"] = id
+		if bundleID == "" {
+			attributes["bundle_identifier"] = "com.example.app" + storeID
+		}
+	case bundleID != "":
+		attributes["itunes_store_id"] = storeIDFromBundleID(bundleID)
+	default:
+		writeError(w, http.StatusUnprocessableEntity, "app_store_id, bundle_id or binary is required")
+		return
+	}
+
+	if name == "" {
+		name = appNameFromBundleID(attributes["bundle_identifier"].(string))
+	}
+	attributes["name"] = name
+
+	if message := munkiSettingsError(p, attributes); message != "" {
+		writeError(w, http.StatusUnprocessableEntity, message)
+		return
+	}
+	applyMunkiSettings(p, attributes)
+
+	rec := s.insert(tableApps, attributes)
+	rec.content = binary
+
+	writeObject(w, http.StatusCreated, s.renderApp(rec))
+}
+
+// storeIDFromBundleID stands in for the App Store lookup the API performs
+// for apps added by bundle ID, deriving a stable store ID from it.
+func storeIDFromBundleID(bundleID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(bundleID))
+	return 1000000000 + int(h.Sum32()%100000000)
+}
+
+func appNameFromBundleID(bundleID string) string {
+	return bundleID[strings.LastIndex(bundleID, ".")+1:]
+}
+
+func (s *Server) updateApp(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableApps, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	if p.has("deploy_to") && !slices.Contains(appDeployTargets, p.get("deploy_to")) {
+		writeError(w, http.StatusUnprocessableEntity, "deploy_to must be one of none, outdated or all")
+		return
+	}
+
+	if message := munkiSettingsError(p, rec.attributes); message != "" {
+		writeError(w, http.StatusUnprocessableEntity, message)
+		return
+	}
+
+	if binary, ok := p.files["binary"]; ok {
+		if rec.attributes["app_type"] != "enterprise" {
+			writeError(w, http.StatusUnprocessableEntity, "only uploaded apps accept a new binary")
+			return
+		}
+		version := bumpVersion(rec.attributes["version"].(string))
+		if metadata, err := appbinary.Read(bytes.NewReader(binary), int64(len(binary))); err == nil {
+			if metadata.BundleID != rec.attributes["bundle_identifier"] {
+				writeError(w, http.StatusUnprocessableEntity, "binary bundle identifier does not match the app")
+				return
+			}
+			if metadata.Version != "" {
+				version = metadata.Version
+			}
+		}
+		rec.content = binary
+		rec.attributes["version"] = version
+		rec.attributes["processing_status"] = "processing"
+	}
+	if name := p.get("name"); name != "" {
+		rec.attributes["name"] = name
+	}
+	if p.has("deploy_to") {
+		rec.attributes["deploy_to"] = p.get("deploy_to")
+	}
+	applyMunkiSettings(p, rec.attributes)
+	s.touch(rec)
+
+	writeObject(w, http.StatusOK, s.renderApp(rec))
+}
+
+// munkiSettingsError returns why the Munki settings among p cannot be stored
+// in the app with the given attributes, or an empty string when they can.
+// Only uploaded apps have Munki settings.
+func munkiSettingsError(p params, attributes map[string]any) string {
+	if !slices.ContainsFunc(appMunkiSettings, p.has) && !p.has("blocking_applications") {
+		return ""
+	}
+
+	switch {
+	case attributes["app_type"] != "enterprise":
+		return "munki settings are only available for uploaded apps"
+	case p.has("installer_type") && !slices.Contains(appInstallerTypes, p.get("installer_type")):
+		return "installer_type is not included in the list"
+	case p.has("uninstall_method") && !slices.Contains(appUninstallMethods, p.get("uninstall_method")):
+		return "uninstall_method is not included in the list"
+	case p.get("minimum_os_version") != "" && !appMinimumOSVersionRE.MatchString(p.get("minimum_os_version")):
+		return "minimum_os_version is invalid"
+	}
+
+	return ""
+}
+
+// applyMunkiSettings stores the Munki settings among p in attributes. Lists
+// are sent as comma separated values.
+func applyMunkiSettings(p params, attributes map[string]any) {
+	for _, name := range appMunkiSettings {
+		if p.has(name) {
+			attributes[name] = p.get(name)
+		}
+	}
+	if p.has("blocking_applications") {
+		attributes["blocking_applications"] = p.list("blocking_applications")
+	}
+}
+
+// bumpVersion increments the minor version of an uploaded app.
+func bumpVersion(version string) string {
+	major, minor, _ := strings.Cut(version, ".")
+	n, _ := strconv.Atoi(minor)
+
+	return major + "." + strconv.Itoa(n+1)
+}
+
+func (s *Server) deleteApp(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableApps, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	for _, config := range s.managedConfigsOf(rec.id) {
+		s.remove(tableManagedConfigs, config.id)
+	}
+	s.remove(tableApps, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// managedConfigsOf returns the managed configs of an app ordered by ID.
+func (s *Server) managedConfigsOf(appID int) []*record {
+	var configs []*record
+	for _, rec := range s.sorted(tableManagedConfigs) {
+		if rec.attributes["app_id"] == appID {
+			configs = append(configs, rec)
+		}
+	}
+
+	return configs
+}
+
+func renderManagedConfig(rec *record) map[string]any {
+	return object("managed_config", rec.id, map[string]any{
+		"key":        rec.attributes["key"],
+		"value":      rec.attributes["value"],
+		"value_type": rec.attributes["value_type"],
+	}, nil)
+}
+
+func (s *Server) listManagedConfigs(w http.ResponseWriter, r *http.Request) {
+	app, ok := s.lookup(tableApps, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	objects := []map[string]any{}
+	for _, rec := range s.managedConfigsOf(app.id) {
+		objects = append(objects, renderManagedConfig(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) createManagedConfig(w http.ResponseWriter, r *http.Request) {
+	app, ok := s.lookup(tableApps, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	key, valueType := p.get("key"), p.get("value_type")
+	switch {
+	case key == "":
+		writeError(w, http.StatusUnprocessableEntity, "key can't be blank")
+		return
+	case !slices.Contains(managedConfigTypes, valueType):
+		writeError(w, http.StatusUnprocessableEntity, "value_type is not included in the list")
+		return
+	}
+
+	for _, existing := range s.managedConfigsOf(app.id) {
+		if existing.attributes["key"] == key {
+			writeError(w, http.StatusUnprocessableEntity, "key has already been taken")
+			return
+		}
+	}
+
+	rec := s.insert(tableManagedConfigs, map[string]any{
+		"app_id":     app.id,
+		"key":        key,
+		"value":      p.get("value"),
+		"value_type": valueType,
+	})
+
+	writeObject(w, http.StatusCreated, renderManagedConfig(rec))
+}
+
+func (s *Server) pushManagedConfigs(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.lookup(tableApps, r.PathValue("id")); !ok {
+		writeNotFound(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) deleteManagedConfig(w http.ResponseWriter, r *http.Request) {
+	app, ok := s.lookup(tableApps, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	rec, ok := s.lookup(tableManagedConfigs, r.PathValue("config"))
+	if !ok || rec.attributes["app_id"] != app.id {
+		writeNotFound(w)
+		return
+	}
+
+	s.remove(tableManagedConfigs, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
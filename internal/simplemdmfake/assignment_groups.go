@@ -0,0 +1,270 @@
+package simplemdmfake
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+var (
+	assignmentGroupTypes        = []string{"standard", "munki"}
+	assignmentGroupInstallTypes = []string{"managed", "self_serve", "managed_updates", "default_installs"}
+)
+
+// assignmentGroupObjects maps the object collections of an assignment group
+// to the tables holding them.
+var assignmentGroupObjects = map[string]string{
+	"apps":          tableApps,
+	"profiles":      tableProfiles,
+	"devices":       tableDevices,
+	"device_groups": tableDeviceGroups,
+}
+
+func (s *Server) registerAssignmentGroups() {
+	s.handle(http.MethodGet, "/assignment_groups", s.listAssignmentGroups)
+	s.handle(http.MethodPost, "/assignment_groups", s.createAssignmentGroup)
+	s.handle(http.MethodGet, "/assignment_groups/{id}", s.getAssignmentGroup)
+	s.handle(http.MethodPatch, "/assignment_groups/{id}", s.updateAssignmentGroup)
+	s.handle(http.MethodDelete, "/assignment_groups/{id}", s.deleteAssignmentGroup)
+
+	s.handle(http.MethodPost, "/assignment_groups/{id}/push_apps", s.assignmentGroupAction(http.StatusAccepted))
+	s.handle(http.MethodPost, "/assignment_groups/{id}/update_apps", s.assignmentGroupAction(http.StatusAccepted))
+	s.handle(http.MethodPost, "/assignment_groups/{id}/sync_profiles", s.assignmentGroupAction(http.StatusNoContent))
+
+	s.handle(http.MethodPost, "/assignment_groups/{id}/{collection}/{object}", s.assignToAssignmentGroup)
+	s.handle(http.MethodDelete, "/assignment_groups/{id}/{collection}/{object}", s.unassignFromAssignmentGroup)
+}
+
+func (s *Server) renderAssignmentGroup(rec *record) map[string]any {
+	attributes := map[string]any{
+		"name":               rec.attributes["name"],
+		"auto_deploy":        rec.attributes["auto_deploy"],
+		"type":               rec.attributes["type"],
+		"priority":           rec.attributes["priority"],
+		"app_track_location": rec.attributes["app_track_location"],
+		"device_count":       len(rec.links["devices"]),
+		"group_count":        len(rec.links["device_groups"]),
+		"created_at":         rec.attributes["created_at"],
+		"updated_at":         rec.attributes["updated_at"],
+	}
+	if rec.attributes["type"] == "munki" {
+		attributes["install_type"] = rec.attributes["install_type"]
+	}
+
+	return object("assignment_group", rec.id, attributes, map[string]any{
+		"apps":          references("app", rec.links["apps"]),
+		"profiles":      s.profileReferences(rec.links["profiles"]),
+		"devices":       references("device", rec.links["devices"]),
+		"device_groups": references("device_group", rec.links["device_groups"]),
+	})
+}
+
+func (s *Server) listAssignmentGroups(w http.ResponseWriter, r *http.Request) {
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableAssignmentGroups) {
+		objects = append(objects, s.renderAssignmentGroup(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) getAssignmentGroup(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableAssignmentGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, s.renderAssignmentGroup(rec))
+}
+
+func (s *Server) createAssignmentGroup(w http.ResponseWriter, r *http.Request) {
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	if p.get("name") == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name can't be blank")
+		return
+	}
+
+	attributes := map[string]any{
+		"auto_deploy":        true,
+		"type":               "standard",
+		"install_type":       "managed",
+		"priority":           0,
+		"app_track_location": true,
+	}
+	if !applyAssignmentGroupParams(w, p, attributes) {
+		return
+	}
+
+	rec := s.insert(tableAssignmentGroups, attributes)
+
+	writeObject(w, http.StatusCreated, s.renderAssignmentGroup(rec))
+}
+
+func (s *Server) updateAssignmentGroup(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableAssignmentGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	if p.has("type") && p.get("type") != rec.attributes["type"] {
+		writeError(w, http.StatusUnprocessableEntity, "type can't be changed")
+		return
+	}
+	if !applyAssignmentGroupParams(w, p, rec.attributes) {
+		return
+	}
+	s.touch(rec)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// applyAssignmentGroupParams validates the create and update parameters and
+// copies them into attributes.
+func applyAssignmentGroupParams(w http.ResponseWriter, p params, attributes map[string]any) bool {
+	if p.has("type") && !slices.Contains(assignmentGroupTypes, p.get("type")) {
+		writeError(w, http.StatusUnprocessableEntity, "type must be standard or munki")
+		return false
+	}
+	if p.has("install_type") && !slices.Contains(assignmentGroupInstallTypes, p.get("install_type")) {
+		writeError(w, http.StatusUnprocessableEntity, "install_type is not included in the list")
+		return false
+	}
+
+	var priority int
+	if p.has("priority") {
+		var err error
+		if priority, err = strconv.Atoi(p.get("priority")); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "priority must be an integer")
+			return false
+		}
+	}
+
+	if name := p.get("name"); name != "" {
+		attributes["name"] = name
+	}
+	if autoDeploy, ok := p.boolean("auto_deploy"); ok {
+		attributes["auto_deploy"] = autoDeploy
+	}
+	if p.has("type") {
+		attributes["type"] = p.get("type")
+	}
+	if p.has("install_type") {
+		attributes["install_type"] = p.get("install_type")
+	}
+	if p.has("priority") {
+		attributes["priority"] = priority
+	}
+	if trackLocation, ok := p.boolean("app_track_location"); ok {
+		attributes["app_track_location"] = trackLocation
+	}
+
+	return true
+}
+
+func (s *Server) deleteAssignmentGroup(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableAssignmentGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.remove(tableAssignmentGroups, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// assignmentGroupAction answers the push_apps, update_apps and
+// sync_profiles requests, which only queue work on the devices.
+func (s *Server) assignmentGroupAction(status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.lookup(tableAssignmentGroups, r.PathValue("id")); !ok {
+			writeNotFound(w)
+			return
+		}
+
+		w.WriteHeader(status)
+	}
+}
+
+// assignmentGroupTarget resolves the assignment group and object of an
+// assignment request, answering 404 when either does not exist.
+func (s *Server) assignmentGroupTarget(w http.ResponseWriter, r *http.Request) (*record, string, int, bool) {
+	group, ok := s.lookup(tableAssignmentGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return nil, "", 0, false
+	}
+
+	collection := r.PathValue("collection")
+	table, ok := assignmentGroupObjects[collection]
+	if !ok {
+		writeNotFound(w)
+		return nil, "", 0, false
+	}
+
+	id := r.PathValue("object")
+	if _, ok := s.lookup(table, id); !ok {
+		if table != tableProfiles {
+			writeNotFound(w)
+			return nil, "", 0, false
+		}
+		// Profiles of every kind can be assigned to assignment groups.
+		if _, ok := s.lookup(tableCustomProfiles, id); !ok {
+			writeNotFound(w)
+			return nil, "", 0, false
+		}
+	}
+
+	objectID, _ := strconv.Atoi(id)
+	return group, collection, objectID, true
+}
+
+func (s *Server) assignToAssignmentGroup(w http.ResponseWriter, r *http.Request) {
+	group, collection, objectID, ok := s.assignmentGroupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	// Apps take an optional per-app install_type, which the API does not
+	// report back.
+	if installType := r.URL.Query().Get("install_type"); collection == "apps" && installType != "" && !slices.Contains(assignmentGroupInstallTypes, installType) {
+		writeError(w, http.StatusUnprocessableEntity, "install_type is not included in the list")
+		return
+	}
+
+	if collection == "devices" && r.URL.Query().Get("remove_others") == "true" {
+		for _, other := range s.tables[tableAssignmentGroups] {
+			if other != group {
+				other.unlink("devices", objectID)
+			}
+		}
+	}
+
+	group.link(collection, objectID)
+	s.touch(group)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) unassignFromAssignmentGroup(w http.ResponseWriter, r *http.Request) {
+	group, collection, objectID, ok := s.assignmentGroupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	group.unlink(collection, objectID)
+	s.touch(group)
+
+	w.WriteHeader(http.StatusNoContent)
+}
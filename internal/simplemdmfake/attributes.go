@@ -0,0 +1,223 @@
+package simplemdmfake
+
+import (
+	"net/http"
+)
+
+func (s *Server) registerAttributes() {
+	s.handle(http.MethodGet, "/custom_attributes", s.listAttributes)
+	s.handle(http.MethodPost, "/custom_attributes", s.createAttribute)
+	s.handle(http.MethodGet, "/custom_attributes/{name}", s.getAttribute)
+	s.handle(http.MethodPatch, "/custom_attributes/{name}", s.updateAttribute)
+	s.handle(http.MethodDelete, "/custom_attributes/{name}", s.deleteAttribute)
+
+	s.handle(http.MethodGet, "/device_groups/{id}/custom_attribute_values", s.listGroupAttributeValues)
+	s.handle(http.MethodPut, "/device_groups/{id}/custom_attribute_values/{name}", s.setAttributeValue(tableDeviceGroups))
+	s.handle(http.MethodGet, "/devices/{id}/custom_attribute_values", s.listDeviceAttributeValues)
+	s.handle(http.MethodPut, "/devices/{id}/custom_attribute_values/{name}", s.setAttributeValue(tableDevices))
+}
+
+// attribute returns the custom attribute with the given name. Custom
+// attributes are identified by name rather than by numeric ID.
+func (s *Server) attribute(name string) (*record, bool) {
+	for _, rec := range s.tables[tableCustomAttributes] {
+		if rec.attributes["name"] == name {
+			return rec, true
+		}
+	}
+
+	return nil, false
+}
+
+func renderAttribute(rec *record) map[string]any {
+	return object("custom_attribute", rec.attributes["name"], map[string]any{
+		"name":          rec.attributes["name"],
+		"default_value": rec.attributes["default_value"],
+	}, nil)
+}
+
+func (s *Server) listAttributes(w http.ResponseWriter, r *http.Request) {
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableCustomAttributes) {
+		objects = append(objects, renderAttribute(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) getAttribute(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.attribute(r.PathValue("name"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, renderAttribute(rec))
+}
+
+func (s *Server) createAttribute(w http.ResponseWriter, r *http.Request) {
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	name := p.get("name")
+	if name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name can't be blank")
+		return
+	}
+	if _, exists := s.attribute(name); exists {
+		writeError(w, http.StatusUnprocessableEntity, "name has already been taken")
+		return
+	}
+
+	rec := s.insert(tableCustomAttributes, map[string]any{
+		"name":          name,
+		"default_value": p.get("default_value"),
+	})
+
+	writeObject(w, http.StatusCreated, renderAttribute(rec))
+}
+
+func (s *Server) updateAttribute(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.attribute(r.PathValue("name"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	if p.has("default_value") {
+		rec.attributes["default_value"] = p.get("default_value")
+	}
+	s.touch(rec)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteAttribute(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.attribute(r.PathValue("name"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	name := rec.attributes["name"].(string)
+	for _, table := range []string{tableDevices, tableDeviceGroups} {
+		for _, holder := range s.tables[table] {
+			delete(holder.values, name)
+		}
+	}
+	s.remove(tableCustomAttributes, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setAttributeValue sets the value of a custom attribute on a device or
+// device group.
+func (s *Server) setAttributeValue(table string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		holder, ok := s.lookup(table, r.PathValue("id"))
+		if !ok {
+			writeNotFound(w)
+			return
+		}
+
+		attribute, ok := s.attribute(r.PathValue("name"))
+		if !ok {
+			writeNotFound(w)
+			return
+		}
+
+		p, ok := readParams(w, r)
+		if !ok {
+			return
+		}
+
+		name := attribute.attributes["name"].(string)
+		holder.values[name] = p.get("value")
+		s.touch(holder)
+
+		writeObject(w, http.StatusOK, attributeValue(name, p.get("value"), sourceOf(table)))
+	}
+}
+
+func sourceOf(table string) string {
+	if table == tableDeviceGroups {
+		return "group"
+	}
+
+	return "device"
+}
+
+func attributeValue(name, value, source string) map[string]any {
+	return object("custom_attribute_value", name, map[string]any{
+		"value":  value,
+		"source": source,
+		"secret": false,
+	}, nil)
+}
+
+// groupAttributeValue resolves the value of a custom attribute for a device
+// group, falling back to the attribute default.
+func groupAttributeValue(group, attribute *record) (string, string) {
+	name := attribute.attributes["name"].(string)
+	if group != nil {
+		if value, ok := group.values[name]; ok {
+			return value, "group"
+		}
+	}
+
+	return attribute.attributes["default_value"].(string), "default"
+}
+
+// deviceAttributeValue resolves the value of a custom attribute for a
+// device: its own value, then its device group's value, then the default.
+func (s *Server) deviceAttributeValue(device, attribute *record) (string, string) {
+	if value, ok := device.values[attribute.attributes["name"].(string)]; ok {
+		return value, "device"
+	}
+
+	return groupAttributeValue(s.deviceGroupOf(device), attribute)
+}
+
+func (s *Server) listGroupAttributeValues(w http.ResponseWriter, r *http.Request) {
+	group, ok := s.lookup(tableDeviceGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	data := []map[string]any{}
+	for _, attribute := range s.sorted(tableCustomAttributes) {
+		value, source := groupAttributeValue(group, attribute)
+		data = append(data, attributeValue(attribute.attributes["name"].(string), value, source))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"data": data})
+}
+
+func (s *Server) listDeviceAttributeValues(w http.ResponseWriter, r *http.Request) {
+	device, ok := s.lookup(tableDevices, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.deviceAttributeValues(device))
+}
+
+// deviceAttributeValues renders every custom attribute value of a device.
+func (s *Server) deviceAttributeValues(device *record) map[string]any {
+	data := []map[string]any{}
+	for _, attribute := range s.sorted(tableCustomAttributes) {
+		value, source := s.deviceAttributeValue(device, attribute)
+		data = append(data, attributeValue(attribute.attributes["name"].(string), value, source))
+	}
+
+	return map[string]any{"data": data}
+}
@@ -0,0 +1,228 @@
+package simplemdmfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// customDeclarationFields lists the writable custom declaration attributes.
+var customDeclarationFields = []string{
+	"name", "identifier", "declaration_type", "topic", "transport", "description", "platforms",
+	"active", "priority", "user_scope", "attribute_support", "escape_attributes", "activation_predicate",
+}
+
+func (s *Server) registerCustomDeclarations() {
+	s.handle(http.MethodGet, "/custom_declarations", s.listCustomDeclarations)
+	s.handle(http.MethodPost, "/custom_declarations", s.createCustomDeclaration)
+	s.handle(http.MethodGet, "/custom_declarations/{id}", s.getCustomDeclaration)
+	s.handle(http.MethodPatch, "/custom_declarations/{id}", s.updateCustomDeclaration)
+	s.handle(http.MethodDelete, "/custom_declarations/{id}", s.deleteCustomDeclaration)
+	s.handle(http.MethodGet, "/custom_declarations/{id}/download", s.downloadCustomDeclaration)
+	s.handle(http.MethodPost, "/custom_declarations/{id}/devices/{device}", s.assignCustomDeclaration)
+	s.handle(http.MethodDelete, "/custom_declarations/{id}/devices/{device}", s.unassignCustomDeclaration)
+}
+
+// customDeclarationRequest is the JSON:API body of create and update
+// requests.
+type customDeclarationRequest struct {
+	Data struct {
+		Type       string                     `json:"type"`
+		Attributes map[string]json.RawMessage `json:"attributes"`
+	} `json:"data"`
+}
+
+// renderCustomDeclaration renders a declaration. Like the API, it leaves
+// the declaration payload out; clients download it separately. Declaration
+// IDs are strings.
+func renderCustomDeclaration(rec *record) map[string]any {
+	attributes := map[string]any{
+		"profile_identifier": rec.attributes["profile_identifier"],
+		"group_count":        0,
+		"device_count":       len(rec.links["devices"]),
+		"created_at":         rec.attributes["created_at"],
+		"updated_at":         rec.attributes["updated_at"],
+	}
+	for _, field := range customDeclarationFields {
+		attributes[field] = rec.attributes[field]
+	}
+
+	return object("custom_declaration", strconv.Itoa(rec.id), attributes, nil)
+}
+
+func (s *Server) listCustomDeclarations(w http.ResponseWriter, r *http.Request) {
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableCustomDeclarations) {
+		objects = append(objects, renderCustomDeclaration(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) getCustomDeclaration(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableCustomDeclarations, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, renderCustomDeclaration(rec))
+}
+
+func (s *Server) createCustomDeclaration(w http.ResponseWriter, r *http.Request) {
+	var body customDeclarationRequest
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	attributes := map[string]any{}
+	if !applyCustomDeclarationAttributes(w, body.Data.Attributes, attributes) {
+		return
+	}
+	for _, required := range []string{"name", "identifier", "declaration_type"} {
+		if value, _ := attributes[required].(string); value == "" {
+			writeError(w, http.StatusUnprocessableEntity, required+" can't be blank")
+			return
+		}
+	}
+
+	payload := customDeclarationPayload(body.Data.Attributes)
+	if payload == nil {
+		writeError(w, http.StatusUnprocessableEntity, "data can't be blank")
+		return
+	}
+
+	rec := s.insert(tableCustomDeclarations, attributes)
+	rec.content = payload
+	rec.attributes["profile_identifier"] = fmt.Sprintf("com.simplemdm.declaration.%d", rec.id)
+
+	writeObject(w, http.StatusCreated, renderCustomDeclaration(rec))
+}
+
+func (s *Server) updateCustomDeclaration(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableCustomDeclarations, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	var body customDeclarationRequest
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	if !applyCustomDeclarationAttributes(w, body.Data.Attributes, rec.attributes) {
+		return
+	}
+	if payload := customDeclarationPayload(body.Data.Attributes); payload != nil {
+		rec.content = payload
+	}
+	s.touch(rec)
+
+	writeObject(w, http.StatusOK, renderCustomDeclaration(rec))
+}
+
+// applyCustomDeclarationAttributes decodes the writable attributes of a
+// request into attributes, answering 422 when one is malformed.
+func applyCustomDeclarationAttributes(w http.ResponseWriter, raw map[string]json.RawMessage, attributes map[string]any) bool {
+	for _, field := range customDeclarationFields {
+		value, ok := raw[field]
+		if !ok {
+			continue
+		}
+
+		var decoded any
+		if err := json.Unmarshal(value, &decoded); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, field+" is invalid")
+			return false
+		}
+		attributes[field] = decoded
+	}
+
+	return true
+}
+
+// customDeclarationPayload returns the declaration payload of a request,
+// sent as data or as its payload alias.
+func customDeclarationPayload(raw map[string]json.RawMessage) []byte {
+	for _, field := range []string{"data", "payload"} {
+		if value, ok := raw[field]; ok && string(value) != "null" && len(value) > 0 {
+			return value
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) deleteCustomDeclaration(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableCustomDeclarations, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.remove(tableCustomDeclarations, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) downloadCustomDeclaration(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableCustomDeclarations, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(rec.content)
+}
+
+// customDeclarationTarget resolves the declaration and device of an
+// assignment request, answering 404 when either does not exist.
+func (s *Server) customDeclarationTarget(w http.ResponseWriter, r *http.Request) (*record, *record, bool) {
+	declaration, ok := s.lookup(tableCustomDeclarations, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return nil, nil, false
+	}
+
+	device, ok := s.lookup(tableDevices, r.PathValue("device"))
+	if !ok {
+		writeNotFound(w)
+		return nil, nil, false
+	}
+
+	return declaration, device, true
+}
+
+// assignCustomDeclaration assigns a declaration to a device, answering 409
+// when it already is.
+func (s *Server) assignCustomDeclaration(w http.ResponseWriter, r *http.Request) {
+	declaration, device, ok := s.customDeclarationTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if !declaration.link("devices", device.id) {
+		writeError(w, http.StatusConflict, "custom declaration is already assigned")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) unassignCustomDeclaration(w http.ResponseWriter, r *http.Request) {
+	declaration, device, ok := s.customDeclarationTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if !declaration.unlink("devices", device.id) {
+		writeError(w, http.StatusConflict, "custom declaration is not assigned")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
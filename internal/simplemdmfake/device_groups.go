@@ -0,0 +1,151 @@
+package simplemdmfake
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+)
+
+func (s *Server) registerDeviceGroups() {
+	s.handle(http.MethodGet, "/device_groups", s.listDeviceGroups)
+	s.handle(http.MethodPost, "/device_groups", s.createDeviceGroup)
+	s.handle(http.MethodGet, "/device_groups/{id}", s.getDeviceGroup)
+	s.handle(http.MethodPatch, "/device_groups/{id}", s.updateDeviceGroup)
+	s.handle(http.MethodDelete, "/device_groups/{id}", s.deleteDeviceGroup)
+	s.handle(http.MethodPost, "/device_groups/{id}/clone", s.cloneDeviceGroup)
+	s.handle(http.MethodPost, "/device_groups/{id}/devices/{device}", s.assignDeviceToGroup)
+}
+
+func renderDeviceGroup(rec *record) map[string]any {
+	return object("device_group", rec.id, map[string]any{
+		"name":       rec.attributes["name"],
+		"created_at": rec.attributes["created_at"],
+		"updated_at": rec.attributes["updated_at"],
+	}, nil)
+}
+
+func (s *Server) listDeviceGroups(w http.ResponseWriter, r *http.Request) {
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableDeviceGroups) {
+		objects = append(objects, renderDeviceGroup(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) getDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableDeviceGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, renderDeviceGroup(rec))
+}
+
+func (s *Server) createDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	if p.get("name") == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name can't be blank")
+		return
+	}
+
+	rec := s.insert(tableDeviceGroups, map[string]any{"name": p.get("name")})
+
+	writeObject(w, http.StatusCreated, renderDeviceGroup(rec))
+}
+
+// seedDeviceGroup adds a device group and returns its ID. s.mu must be
+// held.
+func (s *Server) seedDeviceGroup(name string) string {
+	return idString(s.insert(tableDeviceGroups, map[string]any{"name": name}))
+}
+
+func (s *Server) updateDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableDeviceGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	if name := p.get("name"); name != "" {
+		rec.attributes["name"] = name
+	}
+	s.touch(rec)
+
+	writeObject(w, http.StatusOK, renderDeviceGroup(rec))
+}
+
+// deleteDeviceGroup deletes a device group. Like the API it refuses to
+// delete a group that still holds devices.
+func (s *Server) deleteDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableDeviceGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	for _, device := range s.tables[tableDevices] {
+		if slices.Contains(device.links["device_group"], rec.id) {
+			writeError(w, http.StatusUnprocessableEntity, "device group still contains devices")
+			return
+		}
+	}
+
+	s.remove(tableDeviceGroups, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cloneDeviceGroup copies a device group together with its custom
+// attribute values and profile assignments.
+func (s *Server) cloneDeviceGroup(w http.ResponseWriter, r *http.Request) {
+	source, ok := s.lookup(tableDeviceGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	clone := s.insert(tableDeviceGroups, map[string]any{
+		"name": source.attributes["name"].(string) + " (Copy)",
+	})
+	clone.values = maps.Clone(source.values)
+
+	for _, table := range []string{tableProfiles, tableCustomProfiles} {
+		for _, profile := range s.tables[table] {
+			if slices.Contains(profile.links["device_groups"], source.id) {
+				profile.link("device_groups", clone.id)
+			}
+		}
+	}
+
+	writeObject(w, http.StatusOK, renderDeviceGroup(clone))
+}
+
+func (s *Server) assignDeviceToGroup(w http.ResponseWriter, r *http.Request) {
+	group, ok := s.lookup(tableDeviceGroups, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	device, ok := s.lookup(tableDevices, r.PathValue("device"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	device.links["device_group"] = []int{group.id}
+	s.touch(device)
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,334 @@
+package simplemdmfake
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// deviceCommands lists the commands accepted on /devices/{id}/{command} by
+// method. set_time_zone is the only one answering 204 instead of 202.
+var deviceCommands = map[string][]string{
+	http.MethodPost: {
+		"push_apps", "refresh", "restart", "shutdown", "lock", "clear_passcode",
+		"clear_firmware_password", "rotate_firmware_password", "clear_recovery_lock_password",
+		"clear_restrictions_password", "rotate_recovery_lock_password", "rotate_filevault_key",
+		"set_admin_password", "rotate_admin_password", "wipe", "update_os", "remote_desktop",
+		"bluetooth", "set_time_zone", "unenroll", "lost_mode",
+	},
+	http.MethodDelete: {"remote_desktop", "bluetooth", "lost_mode"},
+}
+
+func (s *Server) registerDevices() {
+	s.handle(http.MethodGet, "/devices", s.listDevices)
+	s.handle(http.MethodPost, "/devices", s.createDevice)
+	s.handle(http.MethodGet, "/devices/{id}", s.getDevice)
+	s.handle(http.MethodPatch, "/devices/{id}", s.updateDevice)
+	s.handle(http.MethodDelete, "/devices/{id}", s.deleteDevice)
+
+	s.handle(http.MethodGet, "/devices/{id}/{related}", s.listDeviceRelated)
+	s.handle(http.MethodPost, "/devices/{id}/{command}", s.deviceCommand)
+	s.handle(http.MethodDelete, "/devices/{id}/{command}", s.deviceCommand)
+	s.handle(http.MethodPost, "/devices/{id}/lost_mode/{action}", s.lostModeCommand)
+	s.handle(http.MethodDelete, "/devices/{id}/users/{user}", s.deleteDeviceUser)
+}
+
+// deviceGroupOf returns the device group of a device, or nil.
+func (s *Server) deviceGroupOf(device *record) *record {
+	if groups := device.links["device_group"]; len(groups) > 0 {
+		return s.tables[tableDeviceGroups][groups[0]]
+	}
+
+	return nil
+}
+
+func (s *Server) renderDevice(rec *record, includeSecrets bool) map[string]any {
+	group := map[string]any{"data": nil}
+	if deviceGroup := s.deviceGroupOf(rec); deviceGroup != nil {
+		group["data"] = reference("device_group", deviceGroup.id)
+	}
+
+	var declarations []int
+	for _, declaration := range s.sorted(tableCustomDeclarations) {
+		if slices.Contains(declaration.links["devices"], rec.id) {
+			declarations = append(declarations, declaration.id)
+		}
+	}
+	declarationRefs := []map[string]any{}
+	for _, id := range declarations {
+		declarationRefs = append(declarationRefs, map[string]any{"type": "custom_declaration", "id": strconv.Itoa(id)})
+	}
+
+	// The relationship only carries values set on the device itself; the
+	// custom_attribute_values endpoint resolves inherited ones.
+	values := []map[string]any{}
+	for _, attribute := range s.sorted(tableCustomAttributes) {
+		value, source := s.deviceAttributeValue(rec, attribute)
+		if source != "device" {
+			continue
+		}
+		rendered := attributeValue(attribute.attributes["name"].(string), value, source)
+		if includeSecrets || rendered["attributes"].(map[string]any)["secret"] != true {
+			values = append(values, rendered)
+		}
+	}
+
+	return object("device", rec.id, rec.attributes, map[string]any{
+		"device_group":            group,
+		"custom_attribute_values": map[string]any{"data": values},
+		"custom_declarations":     map[string]any{"data": declarationRefs},
+	})
+}
+
+// listDevices lists enrolled devices, optionally including those awaiting
+// enrollment and filtered by a search on names and serial number.
+func (s *Server) listDevices(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	search := strings.ToLower(query.Get("search"))
+	includeAwaiting := query.Get("include_awaiting_enrollment") == "true"
+	includeSecrets := query.Get("include_secret_custom_attributes") == "true"
+
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableDevices) {
+		if rec.attributes["status"] == "awaiting enrollment" && !includeAwaiting {
+			continue
+		}
+		if search != "" && !deviceMatches(rec, search) {
+			continue
+		}
+		objects = append(objects, s.renderDevice(rec, includeSecrets))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func deviceMatches(rec *record, search string) bool {
+	for _, field := range []string{"name", "device_name", "serial_number", "imei"} {
+		if value, ok := rec.attributes[field].(string); ok && strings.Contains(strings.ToLower(value), search) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) getDevice(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableDevices, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	// A device that was sent a command reports a check-in a second after it,
+	// so the check-in is later than the time the command was accepted.
+	if sent, ok := s.checkIns[rec.id]; ok {
+		rec.attributes["last_seen_at"] = sent.Add(time.Second).UTC().Format(timestampLayout)
+		delete(s.checkIns, rec.id)
+	}
+
+	writeObject(w, http.StatusOK, s.renderDevice(rec, r.URL.Query().Get("include_secret_custom_attributes") == "true"))
+}
+
+// createDevice adds a device awaiting enrollment, as the API does.
+func (s *Server) createDevice(w http.ResponseWriter, r *http.Request) {
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	name := p.get("name")
+	if name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name can't be blank")
+		return
+	}
+
+	var group *record
+	if groupID := p.get("group_id"); groupID != "" {
+		if group, ok = s.lookup(tableDeviceGroups, groupID); !ok {
+			writeError(w, http.StatusUnprocessableEntity, "device group not found")
+			return
+		}
+	}
+
+	rec := s.insert(tableDevices, map[string]any{
+		"name":              name,
+		"device_name":       name,
+		"status":            "awaiting enrollment",
+		"serial_number":     nil,
+		"os_version":        nil,
+		"model_name":        nil,
+		"last_seen_at":      nil,
+		"lost_mode_enabled": false,
+	})
+	rec.attributes["enrollment_url"] = fmt.Sprintf("https://a.simplemdm.com/e/?c=%08d", rec.id)
+	if group != nil {
+		rec.link("device_group", group.id)
+	}
+
+	writeObject(w, http.StatusCreated, s.renderDevice(rec, false))
+}
+
+// seedDevice adds an enrolled device and returns its ID. s.mu must be held.
+func (s *Server) seedDevice(name, groupID string) string {
+	rec := s.insert(tableDevices, map[string]any{
+		"name":              name,
+		"device_name":       name,
+		"status":            "enrolled",
+		"enrollment_url":    nil,
+		"os_version":        "17.4",
+		"model_name":        "iPad Pro",
+		"lost_mode_enabled": false,
+	})
+	rec.attributes["serial_number"] = fmt.Sprintf("FAKE%08d", rec.id)
+	rec.attributes["last_seen_at"] = rec.attributes["created_at"]
+	if group, ok := s.lookup(tableDeviceGroups, groupID); ok {
+		rec.link("device_group", group.id)
+	}
+
+	return idString(rec)
+}
+
+func (s *Server) updateDevice(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableDevices, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	if name := p.get("name"); name != "" {
+		rec.attributes["name"] = name
+	}
+	if deviceName := p.get("device_name"); deviceName != "" {
+		rec.attributes["device_name"] = deviceName
+	}
+	s.touch(rec)
+
+	writeObject(w, http.StatusOK, s.renderDevice(rec, false))
+}
+
+func (s *Server) deleteDevice(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableDevices, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.remove(tableDevices, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listDeviceRelated answers the profiles, installed_apps and users listings
+// of a device. The fake does not track what is installed on devices, so
+// they are empty.
+func (s *Server) listDeviceRelated(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.lookup(tableDevices, r.PathValue("id")); !ok {
+		writeNotFound(w)
+		return
+	}
+
+	switch r.PathValue("related") {
+	case "profiles", "installed_apps", "users":
+		writePage(w, r, nil, idCursor)
+	default:
+		writeNotFound(w)
+	}
+}
+
+// deviceCommand queues a device command. The device checks in straight
+// away, so commands waiting for a check-in complete.
+func (s *Server) deviceCommand(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableDevices, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	command := r.PathValue("command")
+	if !slices.Contains(deviceCommands[r.Method], command) {
+		writeNotFound(w)
+		return
+	}
+
+	if _, ok := readParams(w, r); !ok {
+		return
+	}
+
+	switch command {
+	case "lost_mode":
+		rec.attributes["lost_mode_enabled"] = r.Method == http.MethodPost
+	case "update_os":
+		rec.attributes["os_version"] = nextOSVersion(rec.attributes["os_version"])
+	}
+	s.recordCommand(r, rec)
+
+	if command == "set_time_zone" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// nextOSVersion returns the version a device reports after updating.
+func nextOSVersion(current any) string {
+	version, _ := current.(string)
+	major, minor, _ := strings.Cut(version, ".")
+	n, _ := strconv.Atoi(minor)
+
+	return major + "." + strconv.Itoa(n+1)
+}
+
+// recordCommand logs a command sent to a device. The device checks in when
+// it is next looked up.
+func (s *Server) recordCommand(r *http.Request, device *record) {
+	s.commands = append(s.commands, r.Method+" "+r.URL.Path)
+	s.checkIns[device.id] = time.Now()
+}
+
+func (s *Server) lostModeCommand(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableDevices, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	action := r.PathValue("action")
+	if action != "play_sound" && action != "update_location" {
+		writeNotFound(w)
+		return
+	}
+	if rec.attributes["lost_mode_enabled"] != true {
+		writeError(w, http.StatusUnprocessableEntity, "device is not in lost mode")
+		return
+	}
+
+	if action == "update_location" {
+		rec.attributes["location_latitude"] = "37.3349"
+		rec.attributes["location_longitude"] = "-122.00902"
+		rec.attributes["location_accuracy"] = "5"
+		rec.attributes["location_updated_at"] = s.tick()
+	}
+	s.recordCommand(r, rec)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) deleteDeviceUser(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableDevices, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.recordCommand(r, rec)
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,143 @@
+package simplemdmfake
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) registerEnrollments() {
+	s.handle(http.MethodGet, "/enrollments", s.listEnrollments)
+	s.handle(http.MethodPost, "/enrollments", s.createEnrollment)
+	s.handle(http.MethodGet, "/enrollments/{id}", s.getEnrollment)
+	s.handle(http.MethodDelete, "/enrollments/{id}", s.deleteEnrollment)
+	s.handle(http.MethodPost, "/enrollments/{id}/invitations", s.sendEnrollmentInvitation)
+}
+
+func renderEnrollment(rec *record) map[string]any {
+	relationships := map[string]any{
+		"device_group": map[string]any{"data": nil},
+	}
+	if groups := rec.links["device_group"]; len(groups) > 0 {
+		relationships["device_group"] = map[string]any{"data": reference("device_group", groups[0])}
+	}
+	if devices := rec.links["device"]; len(devices) > 0 {
+		relationships["device"] = map[string]any{"data": reference("device", devices[0])}
+	}
+
+	return object("enrollment", rec.id, map[string]any{
+		"url":             rec.attributes["url"],
+		"user_enrollment": rec.attributes["user_enrollment"],
+		"welcome_screen":  rec.attributes["welcome_screen"],
+		"authentication":  rec.attributes["authentication"],
+	}, relationships)
+}
+
+func (s *Server) listEnrollments(w http.ResponseWriter, r *http.Request) {
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableEnrollments) {
+		objects = append(objects, renderEnrollment(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) getEnrollment(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableEnrollments, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, renderEnrollment(rec))
+}
+
+// createEnrollment adds an enrollment for a device group. User enrollments
+// require authentication, as in the API.
+func (s *Server) createEnrollment(w http.ResponseWriter, r *http.Request) {
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	group, ok := s.lookup(tableDeviceGroups, p.get("device_group_id"))
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "device group not found")
+		return
+	}
+
+	userEnrollment, _ := p.boolean("user_enrollment")
+	welcomeScreen, hasWelcomeScreen := p.boolean("welcome_screen")
+	authentication, _ := p.boolean("authentication")
+	if !hasWelcomeScreen {
+		welcomeScreen = true
+	}
+	if userEnrollment && !authentication {
+		writeError(w, http.StatusUnprocessableEntity, "user enrollments require authentication")
+		return
+	}
+
+	rec := s.insert(tableEnrollments, map[string]any{
+		"user_enrollment": userEnrollment,
+		"welcome_screen":  welcomeScreen,
+		"authentication":  authentication,
+	})
+	rec.attributes["url"] = enrollmentURL(rec)
+	rec.link("device_group", group.id)
+
+	writeObject(w, http.StatusCreated, renderEnrollment(rec))
+}
+
+func enrollmentURL(rec *record) string {
+	return fmt.Sprintf("https://a.simplemdm.com/enroll/?c=%08d", rec.id)
+}
+
+// seedEnrollment adds an enrollment for a device group and returns its ID.
+// s.mu must be held.
+func (s *Server) seedEnrollment(groupID string) string {
+	rec := s.insert(tableEnrollments, map[string]any{
+		"user_enrollment": false,
+		"welcome_screen":  true,
+		"authentication":  false,
+	})
+	rec.attributes["url"] = enrollmentURL(rec)
+	if group, ok := s.lookup(tableDeviceGroups, groupID); ok {
+		rec.link("device_group", group.id)
+	}
+
+	return idString(rec)
+}
+
+func (s *Server) deleteEnrollment(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableEnrollments, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.remove(tableEnrollments, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sendEnrollmentInvitation accepts an email address or phone number to
+// send the enrollment link to.
+func (s *Server) sendEnrollmentInvitation(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.lookup(tableEnrollments, r.PathValue("id")); !ok {
+		writeNotFound(w)
+		return
+	}
+
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	contact := strings.TrimSpace(p.get("contact"))
+	if contact == "" {
+		writeError(w, http.StatusUnprocessableEntity, "contact can't be blank")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"contact": contact}})
+}
@@ -0,0 +1,108 @@
+package simplemdmfake
+
+// Fixtures holds the IDs of the objects added by SeedFixtures. They stand in
+// for the objects acceptance tests expect to find in a SimpleMDM test
+// account.
+type Fixtures struct {
+	AppID                  string
+	AssignmentGroupID      string
+	AttributeName          string
+	DeviceGroupID          string
+	CloneSourceGroupID     string
+	DeviceID               string
+	EnrollmentID           string
+	ProfileID              string
+	UpdatedProfileID       string
+	CustomProfileID        string
+	UpdatedCustomProfileID string
+	ScriptID               string
+	ScriptJobID            string
+}
+
+// fixtureMobileconfig is the payload of the seeded custom profiles.
+const fixtureMobileconfig = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>PayloadDisplayName</key>
+	<string>%s</string>
+	<key>PayloadType</key>
+	<string>Configuration</string>
+	<key>PayloadVersion</key>
+	<integer>1</integer>
+</dict>
+</plist>
+`
+
+// SeedFixtures populates the server with one object of each kind, including
+// the enrolled devices and template profiles the API cannot create, and
+// returns their IDs.
+func (s *Server) SeedFixtures() Fixtures {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var f Fixtures
+
+	f.DeviceGroupID = s.seedDeviceGroup("Fixture Devices")
+	f.CloneSourceGroupID = s.seedDeviceGroup("Fixture Clone Source")
+
+	attribute := s.insert(tableCustomAttributes, map[string]any{
+		"name":          "fixture_attribute",
+		"default_value": "default",
+	})
+	f.AttributeName = attribute.attributes["name"].(string)
+
+	f.ProfileID = s.seedProfile("Fixture Restrictions", "com.example.fixture.restrictions")
+	f.UpdatedProfileID = s.seedProfile("Fixture Wi-Fi", "com.example.fixture.wifi")
+	f.CustomProfileID = s.seedCustomProfile("Fixture Custom Profile")
+	f.UpdatedCustomProfileID = s.seedCustomProfile("Fixture Custom Profile Updated")
+
+	app := s.insert(tableApps, map[string]any{
+		"name":                  "Fixture App",
+		"app_type":              "app store",
+		"bundle_identifier":     "com.example.fixture",
+		"itunes_store_id":       1000000001,
+		"version":               "1.0",
+		"platform_support":      "iOS",
+		"processing_status":     "processed",
+		"installation_channels": []string{"standard"},
+		"deploy_to":             "none",
+		"status":                "available",
+	})
+	f.AppID = idString(app)
+
+	assignmentGroup := s.insert(tableAssignmentGroups, map[string]any{
+		"name":               "Fixture Assignment Group",
+		"auto_deploy":        true,
+		"type":               "standard",
+		"install_type":       "managed",
+		"priority":           0,
+		"app_track_location": true,
+	})
+	f.AssignmentGroupID = idString(assignmentGroup)
+
+	f.DeviceID = s.seedDevice("Fixture iPad", f.DeviceGroupID)
+	f.ScriptID = s.seedScript("Fixture Script", "#!/bin/sh\necho fixture\n")
+	f.ScriptJobID = s.seedScriptJob(f.ScriptID, f.DeviceID)
+	f.EnrollmentID = s.seedEnrollment(f.DeviceGroupID)
+
+	return f
+}
+
+// SeedProfile adds a profile such as one built from a template in the
+// SimpleMDM UI, which the API cannot create, and returns its ID.
+func (s *Server) SeedProfile(name, identifier string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.seedProfile(name, identifier)
+}
+
+// SeedDevice adds an enrolled device to a device group and returns its ID.
+// The API can only create devices awaiting enrollment.
+func (s *Server) SeedDevice(name, groupID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.seedDevice(name, groupID)
+}
@@ -0,0 +1,319 @@
+package simplemdmfake
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+)
+
+func (s *Server) registerProfiles() {
+	s.handle(http.MethodGet, "/profiles", s.listProfiles)
+	s.handle(http.MethodGet, "/profiles/{id}", s.getProfile)
+	s.handle(http.MethodPost, "/profiles/{id}/{collection}/{object}", s.assignProfile(tableProfiles))
+	s.handle(http.MethodDelete, "/profiles/{id}/{collection}/{object}", s.unassignProfile(tableProfiles))
+
+	s.handle(http.MethodGet, "/custom_configuration_profiles", s.listCustomProfiles)
+	s.handle(http.MethodPost, "/custom_configuration_profiles", s.createCustomProfile)
+	s.handle(http.MethodGet, "/custom_configuration_profiles/{id}", s.getCustomProfile)
+	s.handle(http.MethodPatch, "/custom_configuration_profiles/{id}", s.updateCustomProfile)
+	s.handle(http.MethodDelete, "/custom_configuration_profiles/{id}", s.deleteCustomProfile)
+	s.handle(http.MethodGet, "/custom_configuration_profiles/{id}/download", s.downloadCustomProfile)
+	s.handle(http.MethodPost, "/custom_configuration_profiles/{id}/{collection}/{object}", s.assignProfile(tableCustomProfiles))
+	s.handle(http.MethodDelete, "/custom_configuration_profiles/{id}/{collection}/{object}", s.unassignProfile(tableCustomProfiles))
+}
+
+// profileTargets maps the collections a profile can be assigned to to the
+// tables holding them.
+var profileTargets = map[string]string{
+	"device_groups": tableDeviceGroups,
+	"devices":       tableDevices,
+}
+
+// profileKind returns the JSON:API type of the profile with the given ID.
+// Profiles and custom configuration profiles share the profile listing.
+func (s *Server) profileKind(id int) string {
+	if _, ok := s.tables[tableCustomProfiles][id]; ok {
+		return "custom_configuration_profile"
+	}
+
+	return "profile"
+}
+
+func (s *Server) profileReferences(ids []int) map[string]any {
+	data := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, reference(s.profileKind(id), id))
+	}
+
+	return map[string]any{"data": data}
+}
+
+func (s *Server) renderProfile(rec *record) map[string]any {
+	attributes := map[string]any{
+		"name":                      rec.attributes["name"],
+		"reinstall_after_os_update": rec.attributes["reinstall_after_os_update"],
+		"profile_identifier":        rec.attributes["profile_identifier"],
+		"user_scope":                rec.attributes["user_scope"],
+		"attribute_support":         rec.attributes["attribute_support"],
+		"escape_attributes":         rec.attributes["escape_attributes"],
+		"group_count":               len(rec.links["device_groups"]),
+		"device_count":              len(rec.links["devices"]),
+		"created_at":                rec.attributes["created_at"],
+		"updated_at":                rec.attributes["updated_at"],
+	}
+	for _, optional := range []string{"auto_deploy", "install_type", "source"} {
+		if value, ok := rec.attributes[optional]; ok {
+			attributes[optional] = value
+		}
+	}
+	if rec.content != nil {
+		attributes["profile_sha"] = profileSHA(rec.content)
+	}
+
+	return object(s.profileKind(rec.id), rec.id, attributes, map[string]any{
+		"device_groups": references("device_group", rec.links["device_groups"]),
+	})
+}
+
+func profileSHA(content []byte) string {
+	sum := md5.Sum(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// listProfiles lists every profile, custom configuration profiles included,
+// ordered by ID.
+func (s *Server) listProfiles(w http.ResponseWriter, r *http.Request) {
+	records := append(s.sorted(tableProfiles), s.sorted(tableCustomProfiles)...)
+	sortRecords(records)
+
+	objects := []map[string]any{}
+	for _, rec := range records {
+		objects = append(objects, s.renderProfile(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) profile(id string) (*record, bool) {
+	if rec, ok := s.lookup(tableProfiles, id); ok {
+		return rec, true
+	}
+
+	return s.lookup(tableCustomProfiles, id)
+}
+
+func (s *Server) getProfile(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.profile(r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, s.renderProfile(rec))
+}
+
+// seedProfile adds a template profile and returns its ID. s.mu must be held.
+func (s *Server) seedProfile(name, identifier string) string {
+	rec := s.insert(tableProfiles, map[string]any{
+		"name":                      name,
+		"profile_identifier":        identifier,
+		"auto_deploy":               true,
+		"install_type":              "managed",
+		"source":                    "template",
+		"reinstall_after_os_update": false,
+		"user_scope":                false,
+		"attribute_support":         false,
+		"escape_attributes":         false,
+	})
+
+	return idString(rec)
+}
+
+// seedCustomProfile adds a custom configuration profile and returns its ID.
+// s.mu must be held.
+func (s *Server) seedCustomProfile(name string) string {
+	rec := s.insert(tableCustomProfiles, map[string]any{
+		"name":                      name,
+		"reinstall_after_os_update": false,
+		"user_scope":                false,
+		"attribute_support":         false,
+		"escape_attributes":         false,
+	})
+	rec.content = []byte(fmt.Sprintf(fixtureMobileconfig, name))
+	rec.attributes["profile_identifier"] = fmt.Sprintf("com.simplemdm.custom.%d", rec.id)
+
+	return idString(rec)
+}
+
+func (s *Server) listCustomProfiles(w http.ResponseWriter, r *http.Request) {
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableCustomProfiles) {
+		objects = append(objects, s.renderProfile(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) getCustomProfile(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableCustomProfiles, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, s.renderProfile(rec))
+}
+
+func (s *Server) createCustomProfile(w http.ResponseWriter, r *http.Request) {
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	mobileconfig, hasFile := p.files["mobileconfig"]
+	switch {
+	case p.get("name") == "":
+		writeError(w, http.StatusUnprocessableEntity, "name can't be blank")
+		return
+	case !hasFile || len(mobileconfig) == 0:
+		writeError(w, http.StatusUnprocessableEntity, "mobileconfig can't be blank")
+		return
+	}
+
+	attributes := map[string]any{
+		"reinstall_after_os_update": false,
+		"user_scope":                false,
+		"attribute_support":         false,
+		"escape_attributes":         false,
+	}
+	applyCustomProfileParams(p, attributes)
+
+	rec := s.insert(tableCustomProfiles, attributes)
+	rec.content = mobileconfig
+	rec.attributes["profile_identifier"] = fmt.Sprintf("com.simplemdm.custom.%d", rec.id)
+
+	writeObject(w, http.StatusCreated, s.renderProfile(rec))
+}
+
+func applyCustomProfileParams(p params, attributes map[string]any) {
+	if name := p.get("name"); name != "" {
+		attributes["name"] = name
+	}
+	for _, flag := range []string{"user_scope", "attribute_support", "escape_attributes", "reinstall_after_os_update"} {
+		if value, ok := p.boolean(flag); ok {
+			attributes[flag] = value
+		}
+	}
+}
+
+func (s *Server) updateCustomProfile(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableCustomProfiles, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	applyCustomProfileParams(p, rec.attributes)
+	if mobileconfig, ok := p.files["mobileconfig"]; ok && len(mobileconfig) > 0 {
+		rec.content = mobileconfig
+	}
+	s.touch(rec)
+
+	writeObject(w, http.StatusOK, s.renderProfile(rec))
+}
+
+func (s *Server) deleteCustomProfile(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableCustomProfiles, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.remove(tableCustomProfiles, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// downloadCustomProfile answers with the mobileconfig and a weak ETag
+// holding its digest, which clients use to detect changes.
+func (s *Server) downloadCustomProfile(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableCustomProfiles, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-apple-aspen-config")
+	w.Header().Set("ETag", fmt.Sprintf("W/%q", profileSHA(rec.content)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(rec.content)
+}
+
+// profileTarget resolves the profile and target of an assignment request,
+// answering 404 when either does not exist.
+func (s *Server) profileTarget(w http.ResponseWriter, r *http.Request, table string) (*record, string, int, bool) {
+	profile, ok := s.lookup(table, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return nil, "", 0, false
+	}
+
+	collection := r.PathValue("collection")
+	targetTable, ok := profileTargets[collection]
+	if !ok {
+		writeNotFound(w)
+		return nil, "", 0, false
+	}
+
+	target, ok := s.lookup(targetTable, r.PathValue("object"))
+	if !ok {
+		writeNotFound(w)
+		return nil, "", 0, false
+	}
+
+	return profile, collection, target.id, true
+}
+
+// assignProfile assigns a profile to a device or device group, answering
+// 409 when it already is.
+func (s *Server) assignProfile(table string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profile, collection, targetID, ok := s.profileTarget(w, r, table)
+		if !ok {
+			return
+		}
+
+		if !profile.link(collection, targetID) {
+			writeError(w, http.StatusConflict, "profile is already assigned")
+			return
+		}
+		s.touch(profile)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// unassignProfile removes a profile from a device or device group,
+// answering 409 when it is not assigned.
+func (s *Server) unassignProfile(table string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profile, collection, targetID, ok := s.profileTarget(w, r, table)
+		if !ok {
+			return
+		}
+
+		if !profile.unlink(collection, targetID) {
+			writeError(w, http.StatusConflict, "profile is not assigned")
+			return
+		}
+		s.touch(profile)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
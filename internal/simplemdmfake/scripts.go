@@ -0,0 +1,334 @@
+package simplemdmfake
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+func (s *Server) registerScripts() {
+	s.handle(http.MethodGet, "/scripts", s.listScripts)
+	s.handle(http.MethodPost, "/scripts", s.createScript)
+	s.handle(http.MethodGet, "/scripts/{id}", s.getScript)
+	s.handle(http.MethodPatch, "/scripts/{id}", s.updateScript)
+	s.handle(http.MethodDelete, "/scripts/{id}", s.deleteScript)
+
+	s.handle(http.MethodGet, "/script_jobs", s.listScriptJobs)
+	s.handle(http.MethodPost, "/script_jobs", s.createScriptJob)
+	s.handle(http.MethodGet, "/script_jobs/{id}", s.getScriptJob)
+	s.handle(http.MethodDelete, "/script_jobs/{id}", s.cancelScriptJob)
+}
+
+func renderScript(rec *record) map[string]any {
+	return object("script", rec.id, map[string]any{
+		"name":             rec.attributes["name"],
+		"content":          string(rec.content),
+		"variable_support": rec.attributes["variable_support"],
+		"created_by":       rec.attributes["created_by"],
+		"created_at":       rec.attributes["created_at"],
+		"updated_at":       rec.attributes["updated_at"],
+	}, nil)
+}
+
+func (s *Server) listScripts(w http.ResponseWriter, r *http.Request) {
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableScripts) {
+		objects = append(objects, renderScript(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) getScript(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableScripts, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, renderScript(rec))
+}
+
+func (s *Server) createScript(w http.ResponseWriter, r *http.Request) {
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	content, hasFile := p.files["file"]
+	switch {
+	case p.get("name") == "":
+		writeError(w, http.StatusUnprocessableEntity, "name can't be blank")
+		return
+	case !hasFile || len(content) == 0:
+		writeError(w, http.StatusUnprocessableEntity, "file can't be blank")
+		return
+	}
+
+	variableSupport, _ := p.boolean("variable_support")
+	rec := s.insert(tableScripts, map[string]any{
+		"name":             p.get("name"),
+		"variable_support": variableSupport,
+		"created_by":       "API",
+	})
+	rec.content = content
+
+	writeObject(w, http.StatusCreated, renderScript(rec))
+}
+
+// seedScript adds a script and returns its ID. s.mu must be held.
+func (s *Server) seedScript(name, content string) string {
+	rec := s.insert(tableScripts, map[string]any{
+		"name":             name,
+		"variable_support": false,
+		"created_by":       "API",
+	})
+	rec.content = []byte(content)
+
+	return idString(rec)
+}
+
+func (s *Server) updateScript(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableScripts, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	if name := p.get("name"); name != "" {
+		rec.attributes["name"] = name
+	}
+	if variableSupport, ok := p.boolean("variable_support"); ok {
+		rec.attributes["variable_support"] = variableSupport
+	}
+	if content, ok := p.files["file"]; ok && len(content) > 0 {
+		rec.content = content
+	}
+	s.touch(rec)
+
+	writeObject(w, http.StatusOK, renderScript(rec))
+}
+
+func (s *Server) deleteScript(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableScripts, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.remove(tableScripts, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) renderScriptJob(rec *record) map[string]any {
+	devices := []map[string]any{}
+	for _, id := range rec.links["devices"] {
+		devices = append(devices, map[string]any{
+			"type":        "device",
+			"id":          id,
+			"status":      "pending",
+			"status_code": nil,
+			"response":    nil,
+		})
+	}
+
+	customAttribute := map[string]any{"data": nil}
+	if name, ok := rec.attributes["custom_attribute"].(string); ok {
+		customAttribute["data"] = map[string]any{"type": "custom_attribute", "id": name}
+	}
+
+	script := map[string]any{"data": nil}
+	if scripts := rec.links["script"]; len(scripts) > 0 {
+		script["data"] = reference("script", scripts[0])
+	}
+
+	assignmentGroup := map[string]any{"data": nil}
+	if groups := rec.links["assignment_group"]; len(groups) > 0 {
+		assignmentGroup["data"] = reference("assignment_group", groups[0])
+	}
+
+	return object("script_job", rec.id, map[string]any{
+		"script_name":            rec.attributes["script_name"],
+		"job_name":               rec.attributes["job_name"],
+		"content":                string(rec.content),
+		"job_id":                 rec.attributes["job_id"],
+		"variable_support":       rec.attributes["variable_support"],
+		"status":                 "pending",
+		"pending_count":          len(devices),
+		"success_count":          0,
+		"errored_count":          0,
+		"custom_attribute_regex": rec.attributes["custom_attribute_regex"],
+		"created_by":             "API",
+		"created_at":             rec.attributes["created_at"],
+		"updated_at":             rec.attributes["updated_at"],
+	}, map[string]any{
+		"script":           script,
+		"assignment_group": assignmentGroup,
+		"custom_attribute": customAttribute,
+		"device":           map[string]any{"data": devices},
+	})
+}
+
+func (s *Server) listScriptJobs(w http.ResponseWriter, r *http.Request) {
+	objects := []map[string]any{}
+	for _, rec := range s.sorted(tableScriptJobs) {
+		objects = append(objects, s.renderScriptJob(rec))
+	}
+
+	writePage(w, r, objects, idCursor)
+}
+
+func (s *Server) getScriptJob(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableScriptJobs, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	writeObject(w, http.StatusOK, s.renderScriptJob(rec))
+}
+
+// createScriptJob runs a script on the devices given directly, through
+// device groups and through assignment groups. At least one device must be
+// targeted.
+func (s *Server) createScriptJob(w http.ResponseWriter, r *http.Request) {
+	p, ok := readParams(w, r)
+	if !ok {
+		return
+	}
+
+	script, ok := s.lookup(tableScripts, p.get("script_id"))
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "script not found")
+		return
+	}
+
+	targets, assignmentGroups, ok := s.scriptJobTargets(w, p)
+	if !ok {
+		return
+	}
+	if len(targets) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "at least one device, group or assignment group is required")
+		return
+	}
+
+	attributes := map[string]any{
+		"script_name":      script.attributes["name"],
+		"job_name":         script.attributes["name"],
+		"variable_support": script.attributes["variable_support"],
+	}
+	if name := p.get("custom_attribute"); name != "" {
+		attributes["custom_attribute"] = name
+		attributes["custom_attribute_regex"] = p.get("custom_attribute_regex")
+	}
+
+	rec := s.insert(tableScriptJobs, attributes)
+	rec.content = script.content
+	rec.attributes["job_id"] = fmt.Sprintf("job-%08d", rec.id)
+	rec.link("script", script.id)
+	if len(assignmentGroups) > 0 {
+		rec.link("assignment_group", assignmentGroups[0])
+	}
+	for _, id := range targets {
+		rec.link("devices", id)
+	}
+
+	writeObject(w, http.StatusCreated, s.renderScriptJob(rec))
+}
+
+// scriptJobTargets resolves the devices targeted by a script job request,
+// answering 422 when one of the given objects does not exist.
+func (s *Server) scriptJobTargets(w http.ResponseWriter, p params) ([]int, []int, bool) {
+	var targets, assignmentGroups []int
+	add := func(ids ...int) {
+		for _, id := range ids {
+			if !slices.Contains(targets, id) {
+				targets = append(targets, id)
+			}
+		}
+	}
+
+	for _, id := range p.list("device_ids") {
+		device, ok := s.lookup(tableDevices, id)
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "device "+id+" not found")
+			return nil, nil, false
+		}
+		add(device.id)
+	}
+
+	for _, id := range p.list("group_ids") {
+		group, ok := s.lookup(tableDeviceGroups, id)
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "device group "+id+" not found")
+			return nil, nil, false
+		}
+		add(s.devicesInGroup(group.id)...)
+	}
+
+	for _, id := range p.list("assignment_group_ids") {
+		group, ok := s.lookup(tableAssignmentGroups, id)
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "assignment group "+id+" not found")
+			return nil, nil, false
+		}
+		assignmentGroups = append(assignmentGroups, group.id)
+		add(group.links["devices"]...)
+		for _, deviceGroup := range group.links["device_groups"] {
+			add(s.devicesInGroup(deviceGroup)...)
+		}
+	}
+
+	slices.Sort(targets)
+	return targets, assignmentGroups, true
+}
+
+// devicesInGroup returns the IDs of the devices of a device group.
+func (s *Server) devicesInGroup(groupID int) []int {
+	var ids []int
+	for _, device := range s.sorted(tableDevices) {
+		if slices.Contains(device.links["device_group"], groupID) {
+			ids = append(ids, device.id)
+		}
+	}
+
+	return ids
+}
+
+// seedScriptJob runs a script on a device and returns the job ID. s.mu
+// must be held.
+func (s *Server) seedScriptJob(scriptID, deviceID string) string {
+	script, _ := s.lookup(tableScripts, scriptID)
+	device, _ := s.lookup(tableDevices, deviceID)
+
+	rec := s.insert(tableScriptJobs, map[string]any{
+		"script_name":      script.attributes["name"],
+		"job_name":         script.attributes["name"],
+		"variable_support": script.attributes["variable_support"],
+	})
+	rec.content = script.content
+	rec.attributes["job_id"] = fmt.Sprintf("job-%08d", rec.id)
+	rec.link("script", script.id)
+	rec.link("devices", device.id)
+
+	return idString(rec)
+}
+
+// cancelScriptJob cancels a job that has not run yet.
+func (s *Server) cancelScriptJob(w http.ResponseWriter, r *http.Request) {
+	rec, ok := s.lookup(tableScriptJobs, r.PathValue("id"))
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.remove(tableScriptJobs, rec.id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
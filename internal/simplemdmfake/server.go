@@ -0,0 +1,219 @@
+// Package simplemdmfake implements an in-memory SimpleMDM API server for
+// tests. It serves the parts of the v1 API the provider uses, keeps every
+// object in memory, paginates collections the way SimpleMDM does and answers
+// with the same status codes and error payloads, so acceptance tests can run
+// against it without network access or a SimpleMDM account.
+package simplemdmfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultAPIKey is the API key accepted by a server created without one.
+const DefaultAPIKey = "simplemdm-fake-api-key"
+
+// apiPrefix is the path every API route starts with.
+const apiPrefix = "/api/v1"
+
+// Server is a fake SimpleMDM API listening on a local httptest server.
+type Server struct {
+	// URL is the base URL of the server, suitable as the provider endpoint.
+	URL string
+	// APIKey is the key requests must authenticate with.
+	APIKey string
+
+	httpServer *httptest.Server
+	mux        *http.ServeMux
+
+	mu       sync.Mutex
+	nextID   int
+	clock    time.Time
+	tables   map[string]map[int]*record
+	faults   []*Fault
+	requests []string
+	commands []string
+	// checkIns holds when devices were last sent a command they have not
+	// checked in for yet.
+	checkIns map[int]time.Time
+}
+
+// NewServer starts an empty fake server accepting DefaultAPIKey. Callers
+// must Close it when done.
+func NewServer() *Server {
+	return NewServerWithAPIKey(DefaultAPIKey)
+}
+
+// NewServerWithAPIKey starts an empty fake server accepting apiKey.
+func NewServerWithAPIKey(apiKey string) *Server {
+	s := &Server{
+		APIKey:   apiKey,
+		mux:      http.NewServeMux(),
+		nextID:   1,
+		clock:    time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		tables:   map[string]map[int]*record{},
+		checkIns: map[int]time.Time{},
+	}
+
+	s.registerAccount()
+	s.registerApps()
+	s.registerAssignmentGroups()
+	s.registerAttributes()
+	s.registerProfiles()
+	s.registerDevices()
+	s.registerDeviceGroups()
+	s.registerScripts()
+	s.registerEnrollments()
+	s.registerCustomDeclarations()
+
+	s.httpServer = httptest.NewServer(s)
+	s.URL = s.httpServer.URL
+
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Requests returns every request received so far as "METHOD /path?query".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+// Commands returns the device commands received so far as
+// "METHOD /api/v1/devices/{id}/{command}".
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.commands)
+}
+
+// Fault makes the server answer matching requests with an error instead of
+// handling them.
+type Fault struct {
+	// Method restricts the fault to one HTTP method; empty matches any.
+	Method string
+	// Path is the request path, for example /api/v1/apps/1.
+	Path string
+	// Status is the status code returned, for example 429 or 503.
+	Status int
+	// Times is the number of requests that fail; zero fails all of them.
+	Times int
+	// RetryAfter, when set, is sent as the Retry-After header in seconds.
+	RetryAfter time.Duration
+}
+
+// InjectFault registers f. Faults are matched in the order they were added.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ServeHTTP authenticates the request, applies injected faults and routes it.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The upstream client adds a trailing slash to some collection paths.
+	if r.URL.Path != apiPrefix+"/" {
+		r.URL.Path = strings.TrimSuffix(r.URL.Path, "/")
+	}
+
+	s.mu.Lock()
+	entry := r.Method + " " + r.URL.Path
+	if r.URL.RawQuery != "" {
+		entry += "?" + r.URL.RawQuery
+	}
+	s.requests = append(s.requests, entry)
+	requestID := len(s.requests)
+	fault := s.matchFault(r)
+	s.mu.Unlock()
+
+	w.Header().Set("X-Request-Id", fmt.Sprintf("fake-%d", requestID))
+
+	if key, _, ok := r.BasicAuth(); !ok || key != s.APIKey {
+		writeError(w, http.StatusUnauthorized, "API key is invalid")
+		return
+	}
+
+	if fault != nil {
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(fault.RetryAfter.Seconds())))
+		}
+		writeError(w, fault.Status, http.StatusText(fault.Status))
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// matchFault returns the first fault matching r, consuming one of its
+// failures. s.mu must be held.
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, fault := range s.faults {
+		if fault.Path != r.URL.Path || (fault.Method != "" && fault.Method != r.Method) {
+			continue
+		}
+
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = slices.Delete(s.faults, i, i+1)
+			}
+		}
+
+		return fault
+	}
+
+	return nil
+}
+
+// handle registers handler for pattern, relative to the API prefix, and runs
+// it with the store locked.
+func (s *Server) handle(method, pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(method+" "+apiPrefix+pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		handler(w, r)
+	})
+}
+
+// errorPayload matches the error body SimpleMDM returns.
+type errorPayload struct {
+	Errors []errorItem `json:"errors"`
+}
+
+type errorItem struct {
+	Title string `json:"title"`
+}
+
+func writeError(w http.ResponseWriter, status int, title string) {
+	writeJSON(w, status, errorPayload{Errors: []errorItem{{Title: title}}})
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "object not found")
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// writeObject answers with a single JSON:API object.
+func writeObject(w http.ResponseWriter, status int, object map[string]any) {
+	writeJSON(w, status, map[string]any{"data": object})
+}
@@ -0,0 +1,277 @@
+package simplemdmfake
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+)
+
+// newTestClient starts a fake server and returns it with a client pointed at
+// it. Retries back off for a millisecond only.
+func newTestClient(t *testing.T, apiKey string) (*Server, *simplemdm.Client) {
+	t.Helper()
+
+	server := NewServer()
+	t.Cleanup(server.Close)
+
+	endpoint, err := simplemdmext.ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := simplemdmext.DefaultClientOptions()
+	options.Endpoint = endpoint
+	options.RetryMinWait = time.Millisecond
+	options.RetryMaxWait = time.Millisecond
+	options.RequestsPerSecond = 0
+	client, err := simplemdmext.NewClient("", apiKey, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return server, client
+}
+
+func get(t *testing.T, client *simplemdm.Client, path string, expected ...int) ([]byte, error) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, simplemdmext.APIURL(client, "%s", path), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return simplemdmext.Do(client, req, expected...)
+}
+
+type deviceGroupRecord struct {
+	ID int `json:"id"`
+}
+
+func TestListAllWalksEveryPage(t *testing.T) {
+	server, client := newTestClient(t, DefaultAPIKey)
+
+	server.mu.Lock()
+	for i := range 25 {
+		server.seedDeviceGroup("Group " + strconv.Itoa(i))
+	}
+	server.mu.Unlock()
+
+	groups, err := simplemdmext.ListAll(context.Background(), client, "device_groups", simplemdmext.ListOptions{Limit: 10},
+		simplemdmext.IntCursor(func(group deviceGroupRecord) int { return group.ID }))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 25 {
+		t.Fatalf("got %d groups, want 25", len(groups))
+	}
+
+	pages := 0
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request, "GET /api/v1/device_groups") {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Fatalf("got %d page requests, want 3", pages)
+	}
+}
+
+func TestPageReportsHasMore(t *testing.T) {
+	server, client := newTestClient(t, DefaultAPIKey)
+
+	server.mu.Lock()
+	first := server.seedDeviceGroup("First")
+	server.seedDeviceGroup("Second")
+	server.mu.Unlock()
+
+	body, err := get(t, client, "device_groups?limit=1", http.StatusOK)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var page struct {
+		Data    []deviceGroupRecord `json:"data"`
+		HasMore bool                `json:"has_more"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Data) != 1 || strconv.Itoa(page.Data[0].ID) != first || !page.HasMore {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+
+	body, err = get(t, client, "device_groups?limit=1&starting_after="+first, http.StatusOK)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Data) != 1 || page.HasMore {
+		t.Fatalf("unexpected last page: %+v", page)
+	}
+}
+
+func TestInvalidLimitIsRejected(t *testing.T) {
+	_, client := newTestClient(t, DefaultAPIKey)
+
+	_, err := get(t, client, "apps?limit=101", http.StatusOK)
+
+	apiErr, ok := simplemdmext.AsAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("got %v, want a 400 error", err)
+	}
+}
+
+func TestUnknownObjectIsNotFound(t *testing.T) {
+	_, client := newTestClient(t, DefaultAPIKey)
+
+	_, err := get(t, client, "apps/999", http.StatusOK)
+
+	if !simplemdmext.IsNotFound(err) {
+		t.Fatalf("got %v, want a not found error", err)
+	}
+	if apiErr, _ := simplemdmext.AsAPIError(err); apiErr.RequestID == "" {
+		t.Fatal("expected the request ID to be reported")
+	}
+}
+
+func TestWrongAPIKeyIsUnauthorized(t *testing.T) {
+	_, client := newTestClient(t, "wrong-key")
+
+	_, err := simplemdmext.GetAccount(context.Background(), client)
+
+	apiErr, ok := simplemdmext.AsAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("got %v, want a 401 error", err)
+	}
+}
+
+func TestInjectedFaultIsRetried(t *testing.T) {
+	server, client := newTestClient(t, DefaultAPIKey)
+	server.InjectFault(Fault{Method: http.MethodGet, Path: "/api/v1/account", Status: http.StatusServiceUnavailable, Times: 2})
+
+	account, err := simplemdmext.GetAccount(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.Data.Attributes.Name != "SimpleMDM Fake" {
+		t.Fatalf("unexpected account: %+v", account.Data.Attributes)
+	}
+
+	if requests := server.Requests(); len(requests) != 3 {
+		t.Fatalf("got requests %v, want 3", requests)
+	}
+}
+
+func TestCustomProfileDownloadCarriesDigest(t *testing.T) {
+	_, client := newTestClient(t, DefaultAPIKey)
+
+	mobileconfig := "<plist><dict/></plist>"
+	profile, err := client.CustomProfileCreate("Digest", mobileconfig, false, false, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sha, content, err := client.CustomProfileSHA(strconv.Itoa(profile.Data.ID))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sum := md5.Sum([]byte(mobileconfig))
+	if content != mobileconfig || sha != hex.EncodeToString(sum[:]) {
+		t.Fatalf("got content %q and digest %q", content, sha)
+	}
+}
+
+func TestProfileAssignmentConflicts(t *testing.T) {
+	server, client := newTestClient(t, DefaultAPIKey)
+	fixtures := server.SeedFixtures()
+
+	assign := func() error {
+		req, err := http.NewRequest(http.MethodPost, simplemdmext.APIURL(client, "profiles/%s/device_groups/%s", fixtures.ProfileID, fixtures.DeviceGroupID), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = simplemdmext.Do(client, req, http.StatusNoContent)
+		return err
+	}
+
+	if err := assign(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	apiErr, ok := simplemdmext.AsAPIError(assign())
+	if !ok || apiErr.StatusCode != http.StatusConflict {
+		t.Fatalf("got %v, want a 409 error", apiErr)
+	}
+}
+
+func TestSeedFixturesAreServed(t *testing.T) {
+	server, client := newTestClient(t, DefaultAPIKey)
+	fixtures := server.SeedFixtures()
+
+	for _, path := range []string{
+		"apps/" + fixtures.AppID,
+		"assignment_groups/" + fixtures.AssignmentGroupID,
+		"custom_attributes/" + fixtures.AttributeName,
+		"device_groups/" + fixtures.DeviceGroupID,
+		"device_groups/" + fixtures.CloneSourceGroupID,
+		"devices/" + fixtures.DeviceID,
+		"enrollments/" + fixtures.EnrollmentID,
+		"profiles/" + fixtures.ProfileID,
+		"profiles/" + fixtures.UpdatedProfileID,
+		"custom_configuration_profiles/" + fixtures.CustomProfileID,
+		"custom_configuration_profiles/" + fixtures.UpdatedCustomProfileID,
+		"scripts/" + fixtures.ScriptID,
+		"script_jobs/" + fixtures.ScriptJobID,
+	} {
+		if _, err := get(t, client, path, http.StatusOK); err != nil {
+			t.Errorf("GET %s: %v", path, err)
+		}
+	}
+}
+
+func TestDeviceCommandsAreRecorded(t *testing.T) {
+	server, client := newTestClient(t, DefaultAPIKey)
+	fixtures := server.SeedFixtures()
+
+	for _, command := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodPost, "lost_mode", http.StatusAccepted},
+		{http.MethodPost, "lost_mode/update_location", http.StatusAccepted},
+		{http.MethodPost, "set_time_zone", http.StatusNoContent},
+	} {
+		req, err := http.NewRequest(command.method, simplemdmext.APIURL(client, "devices/%s/%s", fixtures.DeviceID, command.path), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := simplemdmext.Do(client, req, command.status); err != nil {
+			t.Fatalf("%s %s: %v", command.method, command.path, err)
+		}
+	}
+
+	device, err := simplemdmext.GetDevice(context.Background(), client, fixtures.DeviceID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if device.Data.Attributes["lost_mode_enabled"] != true || device.Data.Attributes["location_latitude"] == nil {
+		t.Fatalf("unexpected device attributes: %v", device.Data.Attributes)
+	}
+
+	if commands := server.Commands(); len(commands) != 3 {
+		t.Fatalf("got commands %v, want 3", commands)
+	}
+}
@@ -0,0 +1,366 @@
+package simplemdmfake
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Table names. Records of every table share one ID sequence, like the
+// SimpleMDM object IDs they stand in for, so an ID never names two objects.
+const (
+	tableApps               = "apps"
+	tableManagedConfigs     = "managed_configs"
+	tableAssignmentGroups   = "assignment_groups"
+	tableCustomAttributes   = "custom_attributes"
+	tableProfiles           = "profiles"
+	tableCustomProfiles     = "custom_configuration_profiles"
+	tableDevices            = "devices"
+	tableDeviceGroups       = "device_groups"
+	tableScripts            = "scripts"
+	tableScriptJobs         = "script_jobs"
+	tableEnrollments        = "enrollments"
+	tableCustomDeclarations = "custom_declarations"
+)
+
+const (
+	defaultPageLimit   = 10
+	maxPageLimit       = 100
+	maxMultipartMemory = 32 << 20
+	timestampLayout    = time.RFC3339
+)
+
+// record is an object held by the fake.
+type record struct {
+	id         int
+	attributes map[string]any
+	// links holds to-many relationships by name, in assignment order.
+	links map[string][]int
+	// values holds custom attribute values set directly on the record.
+	values map[string]string
+	// content holds an uploaded file or payload.
+	content []byte
+}
+
+func (r *record) link(name string, id int) bool {
+	if slices.Contains(r.links[name], id) {
+		return false
+	}
+
+	r.links[name] = append(r.links[name], id)
+	return true
+}
+
+func (r *record) unlink(name string, id int) bool {
+	index := slices.Index(r.links[name], id)
+	if index < 0 {
+		return false
+	}
+
+	r.links[name] = slices.Delete(r.links[name], index, index+1)
+	return true
+}
+
+// tick advances the fake clock so successive writes get distinct,
+// deterministic timestamps.
+func (s *Server) tick() string {
+	s.clock = s.clock.Add(time.Second)
+	return s.clock.Format(timestampLayout)
+}
+
+// insert stores a new record in table with the given attributes and
+// creation timestamps.
+func (s *Server) insert(table string, attributes map[string]any) *record {
+	now := s.tick()
+
+	rec := &record{
+		id:         s.nextID,
+		attributes: maps.Clone(attributes),
+		links:      map[string][]int{},
+		values:     map[string]string{},
+	}
+	if rec.attributes == nil {
+		rec.attributes = map[string]any{}
+	}
+	rec.attributes["created_at"] = now
+	rec.attributes["updated_at"] = now
+	s.nextID++
+
+	if s.tables[table] == nil {
+		s.tables[table] = map[int]*record{}
+	}
+	s.tables[table][rec.id] = rec
+
+	return rec
+}
+
+// touch records a modification of rec.
+func (s *Server) touch(rec *record) {
+	rec.attributes["updated_at"] = s.tick()
+}
+
+// lookup returns the record of table identified by the path value id.
+func (s *Server) lookup(table, id string) (*record, bool) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, false
+	}
+
+	rec, ok := s.tables[table][n]
+	return rec, ok
+}
+
+// remove deletes the record of table with the given ID and drops every link
+// pointing to it.
+func (s *Server) remove(table string, id int) {
+	delete(s.tables[table], id)
+
+	for _, records := range s.tables {
+		for _, rec := range records {
+			for name := range rec.links {
+				rec.unlink(name, id)
+			}
+		}
+	}
+}
+
+// idString returns the ID of rec as the API path segment naming it.
+func idString(rec *record) string {
+	return strconv.Itoa(rec.id)
+}
+
+// sorted returns the records of table ordered by ID.
+func (s *Server) sorted(table string) []*record {
+	records := slices.Collect(maps.Values(s.tables[table]))
+	sortRecords(records)
+
+	return records
+}
+
+func sortRecords(records []*record) {
+	slices.SortFunc(records, func(a, b *record) int { return a.id - b.id })
+}
+
+// reference renders a relationship entry.
+func reference(kind string, id int) map[string]any {
+	return map[string]any{"type": kind, "id": id}
+}
+
+func references(kind string, ids []int) map[string]any {
+	data := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, reference(kind, id))
+	}
+
+	return map[string]any{"data": data}
+}
+
+// object renders a JSON:API object.
+func object(kind string, id any, attributes map[string]any, relationships map[string]any) map[string]any {
+	obj := map[string]any{
+		"type":       kind,
+		"id":         id,
+		"attributes": attributes,
+	}
+	if relationships != nil {
+		obj["relationships"] = relationships
+	}
+
+	return obj
+}
+
+// params holds the parameters of a write request. SimpleMDM accepts them in
+// the query string, as a form or as multipart form data.
+type params struct {
+	values url.Values
+	files  map[string][]byte
+}
+
+func (p params) get(name string) string {
+	return p.values.Get(name)
+}
+
+func (p params) has(name string) bool {
+	return p.values.Has(name)
+}
+
+// boolean parses a flag sent as true/false or 1/0.
+func (p params) boolean(name string) (bool, bool) {
+	if !p.values.Has(name) {
+		return false, false
+	}
+
+	switch strings.ToLower(p.values.Get(name)) {
+	case "true", "1":
+		return true, true
+	default:
+		return false, true
+	}
+}
+
+// list parses a comma separated list.
+func (p params) list(name string) []string {
+	var items []string
+	for _, value := range p.values[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items
+}
+
+func parseParams(r *http.Request) (params, error) {
+	p := params{values: url.Values{}, files: map[string][]byte{}}
+	for key, values := range r.URL.Query() {
+		p.values[key] = append(p.values[key], values...)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return p, err
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return p, err
+		}
+		for key, values := range form {
+			p.values[key] = append(p.values[key], values...)
+		}
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+			return p, err
+		}
+		for key, values := range r.MultipartForm.Value {
+			p.values[key] = append(p.values[key], values...)
+		}
+		for key, headers := range r.MultipartForm.File {
+			content, err := readFormFile(headers[0])
+			if err != nil {
+				return p, err
+			}
+			p.files[key] = content
+		}
+	}
+
+	return p, nil
+}
+
+func readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// readParams parses the request parameters, answering 400 when they are
+// malformed.
+func readParams(w http.ResponseWriter, r *http.Request) (params, bool) {
+	p, err := parseParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unable to parse request: "+err.Error())
+		return p, false
+	}
+
+	return p, true
+}
+
+// readJSON decodes a JSON request body into target, answering 400 when it is
+// malformed.
+func readJSON(w http.ResponseWriter, r *http.Request, target any) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(target); err != nil {
+		writeError(w, http.StatusBadRequest, "unable to parse request: "+err.Error())
+		return false
+	}
+
+	return true
+}
+
+var errInvalidLimit = errors.New("limit must be between 1 and 100")
+
+// pageLimit parses the limit query parameter.
+func pageLimit(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return defaultPageLimit, nil
+	}
+
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		return 0, errInvalidLimit
+	}
+
+	return limit, nil
+}
+
+// writePage answers with one page of objects, which must be ordered as they
+// are listed. The page starts after the object whose cursor equals the
+// starting_after parameter; a numeric cursor that matches no object starts
+// after the last object with a smaller numeric cursor, as the API does for
+// deleted objects.
+func writePage(w http.ResponseWriter, r *http.Request, objects []map[string]any, cursor func(map[string]any) string) {
+	limit, err := pageLimit(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	start := 0
+	if after := r.URL.Query().Get("starting_after"); after != "" {
+		start = len(objects)
+		afterNumber, numeric := strconv.Atoi(after)
+		for i, obj := range objects {
+			value := cursor(obj)
+			if value == after {
+				start = i + 1
+				break
+			}
+			if n, err := strconv.Atoi(value); numeric == nil && err == nil && n > afterNumber {
+				start = i
+				break
+			}
+		}
+	}
+
+	end := min(start+limit, len(objects))
+	page := objects[start:end]
+	if page == nil {
+		page = []map[string]any{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data":     page,
+		"has_more": end < len(objects),
+	})
+}
+
+// idCursor is the cursor of objects identified by their ID.
+func idCursor(obj map[string]any) string {
+	switch id := obj["id"].(type) {
+	case int:
+		return strconv.Itoa(id)
+	case string:
+		return id
+	default:
+		return ""
+	}
+}
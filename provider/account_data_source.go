@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &accountDataSource{}
+	_ datasource.DataSourceWithConfigure = &accountDataSource{}
+)
+
+// accountDataSourceModel maps the data source schema data.
+type accountDataSourceModel struct {
+	ID                    types.String `tfsdk:"id"`
+	Name                  types.String `tfsdk:"name"`
+	AppleStoreCountryCode types.String `tfsdk:"apple_store_country_code"`
+	LicensesTotal         types.Int64  `tfsdk:"licenses_total"`
+	LicensesAvailable     types.Int64  `tfsdk:"licenses_available"`
+}
+
+// AccountDataSource is a helper function to simplify the provider implementation.
+func AccountDataSource() datasource.DataSource {
+	return &accountDataSource{}
+}
+
+// accountDataSource is the data source implementation.
+type accountDataSource struct {
+	client *simplemdm.Client
+}
+
+// Metadata returns the data source type name.
+func (d *accountDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account"
+}
+
+// Schema defines the schema for the data source.
+func (d *accountDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Account data source exposes the SimpleMDM account the provider API key belongs to, for example to confirm which account a provider alias manages.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the account. Empty when the API does not report one.",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the account.",
+			},
+			"apple_store_country_code": schema.StringAttribute{
+				Computed:    true,
+				Description: "Country code of the App Store used by the account.",
+			},
+			"licenses_total": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of device licenses in the account subscription.",
+			},
+			"licenses_available": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of device licenses not yet in use.",
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *accountDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	account, err := simplemdmext.GetAccount(ctx, d.client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read SimpleMDM account",
+			err.Error(),
+		)
+		return
+	}
+
+	attributes := account.Data.Attributes
+	state := accountDataSourceModel{
+		ID:                    types.StringValue(account.Data.AccountID()),
+		Name:                  types.StringValue(attributes.Name),
+		AppleStoreCountryCode: types.StringValue(attributes.AppleStoreCountryCode),
+		LicensesTotal:         types.Int64Value(int64(attributes.Subscription.Licenses.Total)),
+		LicensesAvailable:     types.Int64Value(int64(attributes.Subscription.Licenses.Available)),
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *accountDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.client
+}
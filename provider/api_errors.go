@@ -0,0 +1,11 @@
+package provider
+
+import (
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+)
+
+// isNotFoundError reports whether err is a SimpleMDM API error with a 404
+// status code, whether it came from simplemdmext.Do or the upstream client.
+func isNotFoundError(err error) bool {
+	return simplemdmext.IsNotFound(err)
+}
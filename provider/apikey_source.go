@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// apiKeyCommandTimeout bounds how long an apikey_command may run.
+const apiKeyCommandTimeout = 30 * time.Second
+
+// readAPIKeyFile returns the API key stored in the file at path, ignoring
+// surrounding whitespace such as a trailing newline.
+func readAPIKeyFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	apikey := strings.TrimSpace(string(content))
+	if apikey == "" {
+		return "", fmt.Errorf("file %s is empty", path)
+	}
+
+	return apikey, nil
+}
+
+// runAPIKeyCommand executes a credential helper and returns the API key it
+// prints to standard output. The command is run directly, not through a
+// shell: the first element is the executable and the rest are its arguments.
+func runAPIKeyCommand(ctx context.Context, command []string) (string, error) {
+	if len(command) == 0 || command[0] == "" {
+		return "", errors.New("the command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, apiKeyCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("%s did not finish within %s", command[0], apiKeyCommandTimeout)
+		}
+
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%s failed: %w: %s", command[0], err, message)
+		}
+		return "", fmt.Errorf("%s failed: %w", command[0], err)
+	}
+
+	apikey := strings.TrimSpace(stdout.String())
+	if apikey == "" {
+		return "", fmt.Errorf("%s printed no API key", command[0])
+	}
+
+	return apikey, nil
+}
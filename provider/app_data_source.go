@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -170,7 +171,7 @@ func (d *appDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 
 // fetchAppWithParams fetches an app with optional query parameters
 func fetchAppWithParams(ctx context.Context, client *simplemdm.Client, appID string, includeShared types.Bool) (*appAPIResponse, error) {
-	url := fmt.Sprintf("https://%s/api/v1/apps/%s", client.HostName, appID)
+	url := simplemdmext.APIURL(client, "apps/%s", appID)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -183,7 +184,7 @@ func fetchAppWithParams(ctx context.Context, client *simplemdm.Client, appID str
 		req.URL.RawQuery = q.Encode()
 	}
 
-	body, err := client.RequestResponse200(req)
+	body, err := simplemdmext.Do(client, req, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -202,7 +203,7 @@ func (d *appDataSource) Configure(_ context.Context, req datasource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -212,5 +213,5 @@ func (d *appDataSource) Configure(_ context.Context, req datasource.ConfigureReq
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
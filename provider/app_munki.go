@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	// appMunkiInstallerTypes lists the Munki installer types SimpleMDM accepts
+	// for macOS package apps.
+	appMunkiInstallerTypes = []string{"package", "copy_from_dmg", "nopkg"}
+
+	// appMunkiUninstallMethods lists the Munki uninstall methods SimpleMDM
+	// accepts for macOS package apps.
+	appMunkiUninstallMethods = []string{"removepackages", "remove_copied_items", "remove_app", "uninstall_script"}
+
+	// appMinimumOSVersionPattern matches macOS versions such as 13 or 14.2.1.
+	appMinimumOSVersionPattern = regexp.MustCompile(`^\d+(\.\d+){0,2}$`)
+
+	// appStoreAppPaths are the attributes that add an App Store app, which
+	// has no Munki settings.
+	appStoreAppPaths = []path.Expression{path.MatchRoot("app_store_id"), path.MatchRoot("bundle_id")}
+)
+
+// appMunkiFields returns the Munki settings of the plan that differ from
+// prior as form fields. Settings that are null or unknown are left to
+// SimpleMDM; empty values are sent so that they clear the setting.
+func appMunkiFields(plan, prior appResourceModel) []appUploadField {
+	var fields []appUploadField
+
+	settings := []struct {
+		name         string
+		value, prior types.String
+	}{
+		{"installer_type", plan.InstallerType, prior.InstallerType},
+		{"preinstall_script", plan.PreinstallScript, prior.PreinstallScript},
+		{"postinstall_script", plan.PostinstallScript, prior.PostinstallScript},
+		{"uninstall_method", plan.UninstallMethod, prior.UninstallMethod},
+		{"uninstall_script", plan.UninstallScript, prior.UninstallScript},
+		{"minimum_os_version", plan.MinimumOSVersion, prior.MinimumOSVersion},
+	}
+	for _, field := range settings {
+		if !field.value.IsNull() && !field.value.IsUnknown() && !field.value.Equal(field.prior) {
+			fields = append(fields, appUploadField{name: field.name, value: field.value.ValueString()})
+		}
+	}
+
+	if !plan.BlockingApplications.IsNull() && !plan.BlockingApplications.IsUnknown() && !plan.BlockingApplications.Equal(prior.BlockingApplications) {
+		// SimpleMDM takes lists as comma separated values.
+		var apps []string
+		for _, app := range plan.BlockingApplications.Elements() {
+			if app, ok := app.(types.String); ok {
+				apps = append(apps, app.ValueString())
+			}
+		}
+		fields = append(fields, appUploadField{name: "blocking_applications", value: strings.Join(apps, ",")})
+	}
+
+	return fields
+}
+
+// keepAppMunkiSettings keeps empty Munki settings of prior in a model read
+// from the API. SimpleMDM reports a cleared script or list as missing, which
+// would otherwise differ from the empty value in the configuration.
+func keepAppMunkiSettings(model *appResourceModel, prior appResourceModel) {
+	keep := func(value *types.String, prior types.String) {
+		if value.IsNull() && !prior.IsUnknown() && prior.ValueString() == "" {
+			*value = prior
+		}
+	}
+
+	keep(&model.InstallerType, prior.InstallerType)
+	keep(&model.PreinstallScript, prior.PreinstallScript)
+	keep(&model.PostinstallScript, prior.PostinstallScript)
+	keep(&model.UninstallMethod, prior.UninstallMethod)
+	keep(&model.UninstallScript, prior.UninstallScript)
+	keep(&model.MinimumOSVersion, prior.MinimumOSVersion)
+
+	if model.BlockingApplications.IsNull() && !prior.BlockingApplications.IsUnknown() && len(prior.BlockingApplications.Elements()) == 0 {
+		model.BlockingApplications = prior.BlockingApplications
+	}
+}
+
+// appUpdateWithForm updates an app without uploading a binary, sending fields
+// as a form so that long scripts are not limited by the URL length.
+func appUpdateWithForm(ctx context.Context, client *simplemdm.Client, appID string, fields []appUploadField) error {
+	form := url.Values{}
+	for _, field := range fields {
+		form.Set(field.name, field.value)
+	}
+
+	endpoint := simplemdmext.APIURL(client, "apps/%s", appID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	_, err = simplemdmext.Do(client, req, http.StatusOK)
+	return err
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAppMunkiFieldsSendsChangedSettings(t *testing.T) {
+	prior := appResourceModel{
+		InstallerType:        types.StringValue("package"),
+		PreinstallScript:     types.StringValue("echo before"),
+		UninstallMethod:      types.StringValue("removepackages"),
+		BlockingApplications: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Safari")}),
+	}
+	plan := prior
+	plan.PreinstallScript = types.StringValue("")
+	plan.PostinstallScript = types.StringUnknown()
+	plan.BlockingApplications = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Safari"), types.StringValue("Mail")})
+	plan.MinimumOSVersion = types.StringValue("14.2")
+
+	got := appMunkiFields(plan, prior)
+	want := []appUploadField{
+		{name: "preinstall_script", value: ""},
+		{name: "minimum_os_version", value: "14.2"},
+		{name: "blocking_applications", value: "Safari,Mail"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if fields := appMunkiFields(prior, prior); len(fields) != 0 {
+		t.Fatalf("expected no fields for unchanged settings, got %+v", fields)
+	}
+}
+
+func TestKeepAppMunkiSettingsKeepsEmptyValues(t *testing.T) {
+	model, diags := newAppResourceModelFromAPI(context.Background(), &appAPIResponse{})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	prior := appResourceModel{
+		PreinstallScript:     types.StringValue(""),
+		PostinstallScript:    types.StringValue("echo after"),
+		BlockingApplications: types.ListValueMust(types.StringType, []attr.Value{}),
+		MinimumOSVersion:     types.StringUnknown(),
+	}
+	keepAppMunkiSettings(&model, prior)
+
+	if model.PreinstallScript.IsNull() || model.PreinstallScript.ValueString() != "" {
+		t.Fatalf("expected the empty preinstall script to be kept, got %v", model.PreinstallScript)
+	}
+	if !model.PostinstallScript.IsNull() {
+		t.Fatalf("expected a postinstall script missing from the API to be null, got %v", model.PostinstallScript)
+	}
+	if model.BlockingApplications.IsNull() || len(model.BlockingApplications.Elements()) != 0 {
+		t.Fatalf("expected the empty blocking applications to be kept, got %v", model.BlockingApplications)
+	}
+	if !model.MinimumOSVersion.IsNull() {
+		t.Fatalf("expected an unknown minimum OS version to be null, got %v", model.MinimumOSVersion)
+	}
+}
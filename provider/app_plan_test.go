@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// modifyAppPlan runs ModifyPlan for an app whose prior state holds the given
+// attribute values and whose plan replaces some of them.
+func modifyAppPlan(ctx context.Context, t *testing.T, state, replacements map[string]tftypes.Value) (*resource.ModifyPlanResponse, appResourceModel) {
+	t.Helper()
+
+	r := &appResource{}
+	schema, stateValue := resourceTestValue(ctx, t, r, state)
+	planValue := withAttributes(t, stateValue, replacements)
+
+	resp := &resource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: schema, Raw: planValue}}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{
+		State: tfsdk.State{Schema: schema, Raw: stateValue},
+		Plan:  tfsdk.Plan{Schema: schema, Raw: planValue},
+	}, resp)
+
+	var plan appResourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	}
+
+	return resp, plan
+}
+
+func TestAppModifyPlanKeepsDigestWhenBinaryIsMissing(t *testing.T) {
+	ctx := context.Background()
+	binaryPath := filepath.Join(t.TempDir(), "missing.pkg")
+
+	resp, plan := modifyAppPlan(ctx, t, map[string]tftypes.Value{
+		"id":              tftypes.NewValue(tftypes.String, "5"),
+		"binary_file":     tftypes.NewValue(tftypes.String, binaryPath),
+		"binary_sha256":   tftypes.NewValue(tftypes.String, "abc123"),
+		"local_bundle_id": tftypes.NewValue(tftypes.String, "com.example.tools"),
+	}, nil)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if len(resp.Diagnostics.Warnings()) != 1 {
+		t.Fatalf("expected a warning about the missing binary, got %v", resp.Diagnostics)
+	}
+	if plan.BinarySHA256.ValueString() != "abc123" || plan.LocalBundleID.ValueString() != "com.example.tools" {
+		t.Fatalf("expected the digest and bundle id from state, got %+v", plan)
+	}
+}
+
+func TestAppModifyPlanRequiresBinaryWhenPathChanges(t *testing.T) {
+	ctx := context.Background()
+
+	resp, _ := modifyAppPlan(ctx, t, map[string]tftypes.Value{
+		"id":            tftypes.NewValue(tftypes.String, "5"),
+		"binary_file":   tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "old.pkg")),
+		"binary_sha256": tftypes.NewValue(tftypes.String, "abc123"),
+	}, map[string]tftypes.Value{
+		"binary_file": tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "new.pkg")),
+	})
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected a missing binary at a new path to fail the plan")
+	}
+}
+
+func TestAppModifyPlanComparesBundleIDWithLastUpload(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		identifier string
+		wantError  bool
+	}{
+		{name: "same package", identifier: "com.example.tools"},
+		{name: "different package", identifier: "com.example.other", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binaryPath := filepath.Join(t.TempDir(), "tools.pkg")
+			if err := os.WriteFile(binaryPath, testFlatPackage(t, tt.identifier, "2.0"), 0o644); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// SimpleMDM reports the bundle id of the app inside the package,
+			// which differs from the package identifier.
+			resp, plan := modifyAppPlan(ctx, t, map[string]tftypes.Value{
+				"id":              tftypes.NewValue(tftypes.String, "5"),
+				"binary_file":     tftypes.NewValue(tftypes.String, binaryPath),
+				"binary_sha256":   tftypes.NewValue(tftypes.String, "abc123"),
+				"bundle_id":       tftypes.NewValue(tftypes.String, "com.example.Tools"),
+				"local_bundle_id": tftypes.NewValue(tftypes.String, "com.example.tools"),
+			}, nil)
+
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if !tt.wantError && plan.LocalVersion.ValueString() != "2.0" {
+				t.Fatalf("expected the new package to be planned, got %+v", plan)
+			}
+		})
+	}
+}
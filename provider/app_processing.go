@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultAppProcessingTimeout         = 20 * time.Minute
+	defaultAppProcessingMinPollInterval = time.Second
+	defaultAppProcessingMaxPollInterval = 15 * time.Second
+)
+
+var (
+	// appProcessingPending lists the processing_status values reported while
+	// SimpleMDM is still processing an app binary.
+	appProcessingPending = []string{"pending", "processing", "uploading"}
+	// appProcessingFailed lists the processing_status values reported when
+	// SimpleMDM could not process an app binary.
+	appProcessingFailed = []string{"error", "failed", "processing_failed"}
+
+	appTimeoutsAttrTypes = map[string]attr.Type{
+		"create": types.StringType,
+		"update": types.StringType,
+	}
+)
+
+// appTimeoutsBlock limits how long create and update upload binary_file and
+// then wait for SimpleMDM to finish processing the app, so resources
+// depending on it see a processed app. The upload and the wait are each
+// bounded by the timeout.
+func appTimeoutsBlock(ctx context.Context) schema.Block {
+	return timeouts.Block(ctx, timeouts.Opts{
+		Create:            true,
+		Update:            true,
+		CreateDescription: "Maximum time to upload binary_file and then wait for SimpleMDM to process the added app, as a duration such as 30m. Defaults to 20m.",
+		UpdateDescription: "Maximum time to upload a new binary_file and then wait for SimpleMDM to process the updated app, as a duration such as 30m. Defaults to 20m.",
+	})
+}
+
+// appProcessingWait is the resolved wait for an app to be processed.
+type appProcessingWait struct {
+	timeout         time.Duration
+	minPollInterval time.Duration
+	maxPollInterval time.Duration
+}
+
+func newAppProcessingWait(timeout time.Duration) appProcessingWait {
+	return appProcessingWait{
+		timeout:         timeout,
+		minPollInterval: defaultAppProcessingMinPollInterval,
+		maxPollInterval: defaultAppProcessingMaxPollInterval,
+	}
+}
+
+// waitForAppProcessing looks the app up until its processing_status is no
+// longer pending, doubling the interval between lookups up to the maximum.
+// The last app observed is returned in every case, or nil when none could be
+// read. A failed processing status is reported as an error.
+func waitForAppProcessing(ctx context.Context, client *simplemdm.Client, appID string, wait appProcessingWait) (*appAPIResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, wait.timeout)
+	defer cancel()
+
+	var observed *appAPIResponse
+	interval := wait.minPollInterval
+	for {
+		app, err := fetchApp(ctx, client, appID)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			return observed, err
+		case err == nil:
+			observed = app
+
+			status := app.Data.Attributes.ProcessingStatus
+			if slices.Contains(appProcessingFailed, status) {
+				return observed, fmt.Errorf("SimpleMDM could not process app %s: processing_status is %q", appID, status)
+			}
+			if !slices.Contains(appProcessingPending, status) {
+				return observed, nil
+			}
+
+			tflog.Debug(ctx, "Waiting for app processing", map[string]any{
+				"app_id":            appID,
+				"processing_status": status,
+				"next_check":        interval.String(),
+			})
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return observed, fmt.Errorf("app %s was not processed within %s", appID, wait.timeout)
+		case <-timer.C:
+		}
+
+		interval = min(interval*2, wait.maxPollInterval)
+	}
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newAppProcessingTestServer serves app 1, reporting the given processing
+// statuses one lookup after another and repeating the last one.
+func newAppProcessingTestServer(t *testing.T, statuses ...string) (*simplemdm.Client, *atomic.Int32) {
+	t.Helper()
+
+	var lookups atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		index := int(lookups.Add(1)) - 1
+		if index >= len(statuses) {
+			index = len(statuses) - 1
+		}
+		_, _ = w.Write([]byte(`{"data":{"type":"app","id":1,"attributes":{"name":"Tools","processing_status":"` + statuses[index] + `"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	endpoint, err := simplemdmext.ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := simplemdmext.DefaultClientOptions()
+	options.Endpoint = endpoint
+	client, err := simplemdmext.NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return client, &lookups
+}
+
+func TestWaitForAppProcessingReturnsOnceProcessed(t *testing.T) {
+	client, lookups := newAppProcessingTestServer(t, "processing", "processing", "processed")
+
+	wait := appProcessingWait{timeout: 5 * time.Second, minPollInterval: time.Millisecond, maxPollInterval: 2 * time.Millisecond}
+	app, err := waitForAppProcessing(context.Background(), client, "1", wait)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if app.Data.Attributes.ProcessingStatus != "processed" {
+		t.Fatalf("unexpected processing status %q", app.Data.Attributes.ProcessingStatus)
+	}
+	if got := lookups.Load(); got != 3 {
+		t.Fatalf("expected 3 lookups, got %d", got)
+	}
+}
+
+func TestWaitForAppProcessingReportsFailure(t *testing.T) {
+	client, _ := newAppProcessingTestServer(t, "processing", "failed")
+
+	wait := appProcessingWait{timeout: 5 * time.Second, minPollInterval: time.Millisecond, maxPollInterval: time.Millisecond}
+	app, err := waitForAppProcessing(context.Background(), client, "1", wait)
+	if err == nil || !strings.Contains(err.Error(), `processing_status is "failed"`) {
+		t.Fatalf("expected a processing error, got %v", err)
+	}
+	if app == nil || app.Data.Attributes.ProcessingStatus != "failed" {
+		t.Fatalf("expected the failed app to be returned, got %+v", app)
+	}
+}
+
+func TestWaitForAppProcessingTimesOut(t *testing.T) {
+	client, _ := newAppProcessingTestServer(t, "processing")
+
+	wait := appProcessingWait{timeout: 50 * time.Millisecond, minPollInterval: 5 * time.Millisecond, maxPollInterval: 5 * time.Millisecond}
+	app, err := waitForAppProcessing(context.Background(), client, "1", wait)
+	if err == nil || !strings.Contains(err.Error(), "was not processed within 50ms") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if app == nil || app.Data.Attributes.ProcessingStatus != "processing" {
+		t.Fatalf("expected the last app observed to be returned, got %+v", app)
+	}
+}
+
+func TestAppUpdateKeepsPriorDigestWhenProcessingFails(t *testing.T) {
+	client, _ := newAppProcessingTestServer(t, "processing_failed")
+
+	binaryPath := filepath.Join(t.TempDir(), "tools.pkg")
+	if err := os.WriteFile(binaryPath, []byte("pkg-bytes"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	r := &appResource{client: client}
+	values := func(digest string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"id":            tftypes.NewValue(tftypes.String, "1"),
+			"name":          tftypes.NewValue(tftypes.String, "Tools"),
+			"binary_file":   tftypes.NewValue(tftypes.String, binaryPath),
+			"binary_sha256": tftypes.NewValue(tftypes.String, digest),
+		}
+	}
+	schema, planValue := resourceTestValue(ctx, t, r, values("new"))
+	_, stateValue := resourceTestValue(ctx, t, r, values("old"))
+
+	req := resource.UpdateRequest{
+		Plan:  tfsdk.Plan{Schema: schema, Raw: planValue},
+		State: tfsdk.State{Schema: schema, Raw: stateValue},
+	}
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schema, Raw: stateValue}}
+	r.Update(ctx, req, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected the processing failure to be reported")
+	}
+
+	var digest types.String
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("binary_sha256"), &digest)...)
+	if digest.ValueString() != "old" {
+		t.Fatalf("got binary_sha256 %q, want the prior digest", digest.ValueString())
+	}
+}
@@ -1,24 +1,29 @@
 package provider
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"os"
-	"path/filepath"
+	"regexp"
 	"strconv"
-	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/appbinary"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -30,24 +35,36 @@ var (
 	_ resource.Resource                = &appResource{}
 	_ resource.ResourceWithConfigure   = &appResource{}
 	_ resource.ResourceWithImportState = &appResource{}
+	_ resource.ResourceWithModifyPlan  = &appResource{}
 )
 
 // appResourceModel maps the resource schema data.
 type appResourceModel struct {
-	Name                 types.String `tfsdk:"name"`
-	ID                   types.String `tfsdk:"id"`
-	AppStoreId           types.String `tfsdk:"app_store_id"`
-	BundleId             types.String `tfsdk:"bundle_id"`
-	BinaryFile           types.String `tfsdk:"binary_file"`
-	DeployTo             types.String `tfsdk:"deploy_to"`
-	Status               types.String `tfsdk:"status"`
-	AppType              types.String `tfsdk:"app_type"`
-	Version              types.String `tfsdk:"version"`
-	PlatformSupport      types.String `tfsdk:"platform_support"`
-	ProcessingStatus     types.String `tfsdk:"processing_status"`
-	InstallationChannels types.List   `tfsdk:"installation_channels"`
-	CreatedAt            types.String `tfsdk:"created_at"`
-	UpdatedAt            types.String `tfsdk:"updated_at"`
+	Name                 types.String   `tfsdk:"name"`
+	ID                   types.String   `tfsdk:"id"`
+	AppStoreId           types.String   `tfsdk:"app_store_id"`
+	BundleId             types.String   `tfsdk:"bundle_id"`
+	BinaryFile           types.String   `tfsdk:"binary_file"`
+	BinarySHA256         types.String   `tfsdk:"binary_sha256"`
+	LocalBundleID        types.String   `tfsdk:"local_bundle_id"`
+	LocalVersion         types.String   `tfsdk:"local_version"`
+	DeployTo             types.String   `tfsdk:"deploy_to"`
+	Status               types.String   `tfsdk:"status"`
+	AppType              types.String   `tfsdk:"app_type"`
+	Version              types.String   `tfsdk:"version"`
+	PlatformSupport      types.String   `tfsdk:"platform_support"`
+	ProcessingStatus     types.String   `tfsdk:"processing_status"`
+	InstallationChannels types.List     `tfsdk:"installation_channels"`
+	InstallerType        types.String   `tfsdk:"installer_type"`
+	PreinstallScript     types.String   `tfsdk:"preinstall_script"`
+	PostinstallScript    types.String   `tfsdk:"postinstall_script"`
+	UninstallMethod      types.String   `tfsdk:"uninstall_method"`
+	UninstallScript      types.String   `tfsdk:"uninstall_script"`
+	BlockingApplications types.List     `tfsdk:"blocking_applications"`
+	MinimumOSVersion     types.String   `tfsdk:"minimum_os_version"`
+	CreatedAt            types.String   `tfsdk:"created_at"`
+	UpdatedAt            types.String   `tfsdk:"updated_at"`
+	Timeouts             timeouts.Value `tfsdk:"timeouts"`
 }
 
 func AppResource() resource.Resource {
@@ -65,7 +82,7 @@ func (r *appResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -74,7 +91,7 @@ func (r *appResource) Metadata(_ context.Context, req resource.MetadataRequest,
 }
 
 // Schema defines the schema for the resource.
-func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *appResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "App resource can be used to manage Apps.",
 		Attributes: map[string]schema.Attribute{
@@ -126,7 +143,7 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 			},
 			"binary_file": schema.StringAttribute{
 				Optional:    true,
-				Description: "Optional. Absolute or relative path to an app binary (ipa or pkg) to upload. Required when managing enterprise, custom B2B, or macOS package apps.",
+				Description: "Optional. Absolute or relative path to an app binary (ipa or pkg) to upload. Required when managing enterprise, custom B2B, or macOS package apps. When the file is missing locally and the path is unchanged, planning assumes it still matches the binary last uploaded and warns.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -138,6 +155,18 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 					),
 				},
 			},
+			"binary_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 digest of the file at binary_file, computed when planning. When the file changes, the binary is uploaded again in place. When SimpleMDM fails to process the new binary, the previous digest is kept so the next apply uploads it again.",
+			},
+			"local_bundle_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Bundle identifier read from the file at binary_file when planning, from the Info.plist of an ipa or the Distribution or PackageInfo of a flat pkg. A new binary must keep the bundle identifier read from the binary it replaces, or the one SimpleMDM reports when no binary was read before.",
+			},
+			"local_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "Version read from the file at binary_file when planning, known before SimpleMDM has processed the upload.",
+			},
 			"deploy_to": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
@@ -168,13 +197,95 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 			},
 			"processing_status": schema.StringAttribute{
 				Computed:    true,
-				Description: "The current processing status of the app binary within SimpleMDM.",
+				Description: "The current processing status of the app binary within SimpleMDM. Create and update wait until processing has finished.",
 			},
 			"installation_channels": schema.ListAttribute{
 				Computed:    true,
 				ElementType: types.StringType,
 				Description: "The deployment channels supported by the app.",
 			},
+			"installer_type": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Optional. Munki installer type of a macOS package app. Must be one of package, copy_from_dmg or nopkg.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(appMunkiInstallerTypes...),
+					stringvalidator.ConflictsWith(appStoreAppPaths...),
+				},
+			},
+			"preinstall_script": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Optional. Script Munki runs before installing a macOS package app. Set an empty string to remove it.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(appStoreAppPaths...),
+				},
+			},
+			"postinstall_script": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Optional. Script Munki runs after installing a macOS package app. Set an empty string to remove it.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(appStoreAppPaths...),
+				},
+			},
+			"uninstall_method": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Optional. How Munki removes a macOS package app. Must be one of removepackages, remove_copied_items, remove_app or uninstall_script.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(appMunkiUninstallMethods...),
+					stringvalidator.ConflictsWith(appStoreAppPaths...),
+				},
+			},
+			"uninstall_script": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Optional. Script Munki runs to remove a macOS package app when uninstall_method is uninstall_script. Set an empty string to remove it.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(appStoreAppPaths...),
+				},
+			},
+			"blocking_applications": schema.ListAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Optional. Applications that must be closed before Munki installs or removes a macOS package app. Set an empty list to remove them.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1), stringvalidator.RegexMatches(regexp.MustCompile(`^[^,]*$`), "must not contain commas")),
+					listvalidator.ConflictsWith(appStoreAppPaths...),
+				},
+			},
+			"minimum_os_version": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Optional. Lowest macOS version Munki installs a macOS package app on, for example 13.0.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(appMinimumOSVersionPattern, "must be a macOS version such as 13 or 14.2.1"),
+					stringvalidator.ConflictsWith(appStoreAppPaths...),
+				},
+			},
 			"created_at": schema.StringAttribute{
 				Computed:    true,
 				Description: "Timestamp when the app was added to SimpleMDM.",
@@ -184,6 +295,9 @@ func (r *appResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				Description: "Timestamp when the app was last updated in SimpleMDM.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": appTimeoutsBlock(ctx),
+		},
 	}
 }
 
@@ -196,6 +310,13 @@ type appAPIResponse struct {
 			AppType              string   `json:"app_type"`
 			ITunesStoreID        *int     `json:"itunes_store_id"`
 			InstallationChannels []string `json:"installation_channels"`
+			InstallerType        string   `json:"installer_type"`
+			PreinstallScript     string   `json:"preinstall_script"`
+			PostinstallScript    string   `json:"postinstall_script"`
+			UninstallMethod      string   `json:"uninstall_method"`
+			UninstallScript      string   `json:"uninstall_script"`
+			BlockingApplications []string `json:"blocking_applications"`
+			MinimumOSVersion     string   `json:"minimum_os_version"`
 			PlatformSupport      string   `json:"platform_support"`
 			ProcessingStatus     string   `json:"processing_status"`
 			Version              string   `json:"version"`
@@ -212,8 +333,12 @@ func newAppResourceModelFromAPI(ctx context.Context, app *appAPIResponse) (appRe
 
 	// Set computed fields using null for empty values to maintain proper Terraform semantics
 	model := appResourceModel{
-		ID:         types.StringValue(strconv.Itoa(app.Data.ID)),
-		BinaryFile: types.StringNull(),
+		ID:            types.StringValue(strconv.Itoa(app.Data.ID)),
+		BinaryFile:    types.StringNull(),
+		BinarySHA256:  types.StringNull(),
+		LocalBundleID: types.StringNull(),
+		LocalVersion:  types.StringNull(),
+		Timeouts:      timeouts.Value{Object: types.ObjectNull(appTimeoutsAttrTypes)},
 	}
 
 	// Handle optional/computed string fields - use null when empty
@@ -296,17 +421,35 @@ func newAppResourceModelFromAPI(ctx context.Context, app *appAPIResponse) (appRe
 		model.InstallationChannels = types.ListNull(types.StringType)
 	}
 
+	// Munki settings are only reported for macOS package apps
+	model.InstallerType = stringValueOrNull(app.Data.Attributes.InstallerType)
+	model.PreinstallScript = stringValueOrNull(app.Data.Attributes.PreinstallScript)
+	model.PostinstallScript = stringValueOrNull(app.Data.Attributes.PostinstallScript)
+	model.UninstallMethod = stringValueOrNull(app.Data.Attributes.UninstallMethod)
+	model.UninstallScript = stringValueOrNull(app.Data.Attributes.UninstallScript)
+	model.MinimumOSVersion = stringValueOrNull(app.Data.Attributes.MinimumOSVersion)
+
+	if len(app.Data.Attributes.BlockingApplications) > 0 {
+		listValue, listDiags := types.ListValueFrom(ctx, types.StringType, app.Data.Attributes.BlockingApplications)
+		diags.Append(listDiags...)
+		if !listDiags.HasError() {
+			model.BlockingApplications = listValue
+		}
+	} else {
+		model.BlockingApplications = types.ListNull(types.StringType)
+	}
+
 	return model, diags
 }
 
 func fetchApp(ctx context.Context, client *simplemdm.Client, appID string) (*appAPIResponse, error) {
-	url := fmt.Sprintf("https://%s/api/v1/apps/%s", client.HostName, appID)
+	url := simplemdmext.APIURL(client, "apps/%s", appID)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := client.RequestResponse200(req)
+	body, err := simplemdmext.Do(client, req, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -319,47 +462,20 @@ func fetchApp(ctx context.Context, client *simplemdm.Client, appID string) (*app
 	return &app, nil
 }
 
-func (r *appResource) appCreateWithBinary(ctx context.Context, binaryPath, name string) (_ *simplemdm.SimplemdmDefaultStruct, err error) {
-	file, err := os.Open(binaryPath)
-	if err != nil {
-		return nil, fmt.Errorf("unable to open app binary %q: %w", binaryPath, err)
-	}
-	defer func() {
-		if cerr := file.Close(); err == nil && cerr != nil {
-			err = fmt.Errorf("unable to close app binary %q: %w", binaryPath, cerr)
-		}
-	}()
-
-	payload := &bytes.Buffer{}
-	writer := multipart.NewWriter(payload)
-
-	part, err := writer.CreateFormFile("binary", filepath.Base(binaryPath))
-	if err != nil {
-		return nil, fmt.Errorf("unable to create app binary form data: %w", err)
-	}
-
-	if _, err := io.Copy(part, file); err != nil {
-		return nil, fmt.Errorf("unable to read app binary %q: %w", binaryPath, err)
-	}
-
+func (r *appResource) appCreateWithBinary(ctx context.Context, binaryPath, name string, settings ...appUploadField) (*simplemdm.SimplemdmDefaultStruct, error) {
+	var fields []appUploadField
 	if name != "" {
-		if err := writer.WriteField("name", name); err != nil {
-			return nil, fmt.Errorf("unable to encode app name: %w", err)
-		}
-	}
-
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("unable to finalize app upload payload: %w", err)
+		fields = append(fields, appUploadField{name: "name", value: name})
 	}
+	fields = append(fields, settings...)
 
-	url := fmt.Sprintf("https://%s/api/v1/apps", r.client.HostName)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, payload)
+	url := simplemdmext.APIURL(r.client, "apps")
+	req, err := newAppUploadRequest(ctx, http.MethodPost, url, binaryPath, fields...)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	body, err := r.client.RequestResponse201(req)
+	body, err := simplemdmext.Do(r.client, req, http.StatusCreated)
 	if err != nil {
 		return nil, err
 	}
@@ -372,58 +488,176 @@ func (r *appResource) appCreateWithBinary(ctx context.Context, binaryPath, name
 	return &app, nil
 }
 
-func (r *appResource) appUpdateWithBinary(ctx context.Context, appID, binaryPath, name, deployTo string) (err error) {
-	file, err := os.Open(binaryPath)
+func (r *appResource) appUpdateWithBinary(ctx context.Context, appID, binaryPath, name, deployTo string, settings ...appUploadField) error {
+	url := simplemdmext.APIURL(r.client, "apps/%s", appID)
+	req, err := newAppUploadRequest(ctx, http.MethodPatch, url, binaryPath, appUpdateFields(name, deployTo, settings)...)
 	if err != nil {
-		return fmt.Errorf("unable to open app binary %q: %w", binaryPath, err)
+		return err
 	}
-	defer func() {
-		if cerr := file.Close(); err == nil && cerr != nil {
-			err = fmt.Errorf("unable to close app binary %q: %w", binaryPath, cerr)
-		}
-	}()
 
-	payload := &bytes.Buffer{}
-	writer := multipart.NewWriter(payload)
+	_, err = simplemdmext.Do(r.client, req, http.StatusOK)
+	return err
+}
 
-	part, err := writer.CreateFormFile("binary", filepath.Base(binaryPath))
-	if err != nil {
-		return fmt.Errorf("unable to create app binary form data: %w", err)
+// appUpdateFields returns the form fields of an app update. Empty name and
+// deploy_to values leave the app unchanged.
+func appUpdateFields(name, deployTo string, settings []appUploadField) []appUploadField {
+	var fields []appUploadField
+	if name != "" {
+		fields = append(fields, appUploadField{name: "name", value: name})
+	}
+	if deployTo != "" {
+		fields = append(fields, appUploadField{name: "deploy_to", value: deployTo})
 	}
 
-	if _, err := io.Copy(part, file); err != nil {
-		return fmt.Errorf("unable to read app binary %q: %w", binaryPath, err)
+	return append(fields, settings...)
+}
+
+// ModifyPlan hashes the file at binary_file so that rebuilding a binary at
+// the same path plans an update that uploads it again. It also reads the
+// bundle identifier and version from the binary, and rejects a new binary
+// for a different app before anything is uploaded.
+func (r *appResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
 	}
 
-	if name != "" {
-		if err := writer.WriteField("name", name); err != nil {
-			return fmt.Errorf("unable to encode app name: %w", err)
+	var plan, state appResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	hasState := !req.State.Raw.IsNull()
+	if hasState {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case plan.BinaryFile.IsUnknown():
+		plan.BinarySHA256 = types.StringUnknown()
+		plan.LocalBundleID = types.StringUnknown()
+		plan.LocalVersion = types.StringUnknown()
+	case plan.BinaryFile.IsNull():
+		plan.BinarySHA256 = types.StringNull()
+		plan.LocalBundleID = types.StringNull()
+		plan.LocalVersion = types.StringNull()
+	default:
+		sum, err := fileSHA256(plan.BinaryFile.ValueString())
+		if errors.Is(err, os.ErrNotExist) && hasState && plan.BinaryFile.Equal(state.BinaryFile) {
+			// Plan-only and read-only runs may happen on machines without
+			// the artifacts; the binary last uploaded is assumed unchanged.
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("binary_file"),
+				"App binary not found",
+				fmt.Sprintf("%s does not exist on this machine, so the plan assumes it still matches the binary last uploaded. Apply from a machine that has the file to upload a changed binary.", plan.BinaryFile.ValueString()),
+			)
+			plan.BinarySHA256 = state.BinarySHA256
+			plan.LocalBundleID = state.LocalBundleID
+			plan.LocalVersion = state.LocalVersion
+			break
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("binary_file"),
+				"Unable to hash app binary",
+				err.Error(),
+			)
+			return
+		}
+		plan.BinarySHA256 = types.StringValue(sum)
+
+		plan.LocalBundleID = types.StringNull()
+		plan.LocalVersion = types.StringNull()
+		metadata, err := appbinary.Inspect(plan.BinaryFile.ValueString())
+		switch {
+		case errors.Is(err, appbinary.ErrUnsupported):
+			// Only SimpleMDM can tell what the binary contains.
+		case err != nil:
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("binary_file"),
+				"Unable to read app binary metadata",
+				"The bundle identifier and version of the app binary could not be read, so they are only known once SimpleMDM has processed the upload: "+err.Error(),
+			)
+		default:
+			plan.LocalBundleID = types.StringValue(metadata.BundleID)
+			plan.LocalVersion = stringValueOrNull(metadata.Version)
 		}
 	}
 
-	if deployTo != "" {
-		if err := writer.WriteField("deploy_to", deployTo); err != nil {
-			return fmt.Errorf("unable to encode deploy_to value: %w", err)
+	if hasState {
+		// An upload makes SimpleMDM process the app again.
+		if appBinaryChanged(plan, state) {
+			if bundleID := appBundleID(state); bundleID != "" && !plan.LocalBundleID.IsNull() && !plan.LocalBundleID.IsUnknown() && plan.LocalBundleID.ValueString() != bundleID {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("binary_file"),
+					"App binary bundle identifier changed",
+					fmt.Sprintf("The binary at %s is %s, but app %s is %s. A new binary can only replace a version of the same app; manage a different app with its own simplemdm_app resource.",
+						plan.BinaryFile.ValueString(), plan.LocalBundleID.ValueString(), state.ID.ValueString(), bundleID),
+				)
+				return
+			}
+
+			plan.Version = types.StringUnknown()
+			plan.Status = types.StringUnknown()
+			plan.ProcessingStatus = types.StringUnknown()
+			plan.PlatformSupport = types.StringUnknown()
+			plan.InstallationChannels = types.ListUnknown(types.StringType)
+			plan.UpdatedAt = types.StringUnknown()
 		}
 	}
 
-	if err := writer.Close(); err != nil {
-		return fmt.Errorf("unable to finalize app update payload: %w", err)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// appBundleID returns the bundle identifier a new binary of the app in state
+// must have. The identifier read from the binary last uploaded is preferred,
+// since for packages SimpleMDM often reports a different one; the identifier
+// SimpleMDM reports is only used when no binary was read before.
+func appBundleID(state appResourceModel) string {
+	if !state.LocalBundleID.IsNull() && !state.LocalBundleID.IsUnknown() && state.LocalBundleID.ValueString() != "" {
+		return state.LocalBundleID.ValueString()
+	}
+	if !state.BundleId.IsNull() && !state.BundleId.IsUnknown() {
+		return state.BundleId.ValueString()
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/apps/%s", r.client.HostName, appID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, payload)
-	if err != nil {
-		return err
+	return ""
+}
+
+// appBinaryChanged reports whether an update must upload the binary again.
+func appBinaryChanged(plan, state appResourceModel) bool {
+	if plan.BinaryFile.IsNull() {
+		return false
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	_, err = r.client.RequestResponse200(req)
+	if !plan.BinaryFile.Equal(state.BinaryFile) {
+		return true
+	}
+
+	// States written before binary_sha256 existed have no digest to compare
+	// against; they adopt the current one without an upload.
+	return !state.BinarySHA256.IsNull() && !plan.BinarySHA256.Equal(state.BinarySHA256)
+}
+
+// fileSHA256 streams a file through SHA-256 so that large packages are never
+// held in memory.
+func fileSHA256(binaryPath string) (_ string, err error) {
+	file, err := os.Open(binaryPath)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("unable to open app binary %q: %w", binaryPath, err)
 	}
+	defer func() {
+		if cerr := file.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("unable to close app binary %q: %w", binaryPath, cerr)
+		}
+	}()
 
-	return nil
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("unable to read app binary %q: %w", binaryPath, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
 func (r *appResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -433,6 +667,10 @@ func (r *appResource) ImportState(ctx context.Context, req resource.ImportStateR
 
 // Create a new resource
 func (r *appResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	var plan appResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -440,6 +678,12 @@ func (r *appResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
+	timeout, diags := plan.Timeouts.Create(ctx, defaultAppProcessingTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var appStoreId, bundleId, name, binaryPath string
 	if !plan.AppStoreId.IsNull() {
 		appStoreId = plan.AppStoreId.ValueString()
@@ -460,7 +704,10 @@ func (r *appResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	switch {
 	case binaryPath != "":
-		app, err = r.appCreateWithBinary(ctx, binaryPath, name)
+		uploadCtx, cancel := context.WithTimeout(ctx, timeout)
+		app, err = r.appCreateWithBinary(uploadCtx, binaryPath, name, appMunkiFields(plan, appResourceModel{})...)
+		err = appUploadError(ctx, err, timeout)
+		cancel()
 	default:
 		app, err = r.client.AppCreate(
 			appStoreId,
@@ -490,28 +737,36 @@ func (r *appResource) Create(ctx context.Context, req resource.CreateRequest, re
 		}
 	}
 
-	apiApp, err := fetchApp(ctx, r.client, appID)
-	if err != nil {
+	// The app exists from here on, so it is saved even when processing
+	// failed or timed out; Terraform then taints it.
+	apiApp, err := waitForAppProcessing(ctx, r.client, appID, newAppProcessingWait(timeout))
+	if apiApp == nil {
 		resp.Diagnostics.AddError(
 			"Error reading created app",
 			"Could not read newly created app "+appID+": "+err.Error(),
 		)
 		return
 	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for app processing",
+			"App "+appID+" was created, but waiting for SimpleMDM to process it failed: "+err.Error(),
+		)
+	}
 
 	newState, diagsFromAPI := newAppResourceModelFromAPI(ctx, apiApp)
 	resp.Diagnostics.Append(diagsFromAPI...)
-	if resp.Diagnostics.HasError() {
+	if diagsFromAPI.HasError() {
 		return
 	}
 
 	if newState.Name.IsNull() && !plan.Name.IsNull() {
 		newState.Name = plan.Name
 	}
-	if newState.AppStoreId.IsNull() && !plan.AppStoreId.IsNull() {
+	if newState.AppStoreId.IsNull() && !plan.AppStoreId.IsNull() && !plan.AppStoreId.IsUnknown() {
 		newState.AppStoreId = plan.AppStoreId
 	}
-	if newState.BundleId.IsNull() && !plan.BundleId.IsNull() {
+	if newState.BundleId.IsNull() && !plan.BundleId.IsNull() && !plan.BundleId.IsUnknown() {
 		newState.BundleId = plan.BundleId
 	}
 	if (newState.DeployTo.IsNull() || newState.DeployTo.ValueString() == "") && !plan.DeployTo.IsNull() {
@@ -519,13 +774,22 @@ func (r *appResource) Create(ctx context.Context, req resource.CreateRequest, re
 	}
 	if !plan.BinaryFile.IsNull() {
 		newState.BinaryFile = plan.BinaryFile
+		newState.BinarySHA256 = plan.BinarySHA256
+		newState.LocalBundleID = plan.LocalBundleID
+		newState.LocalVersion = plan.LocalVersion
 	}
+	keepAppMunkiSettings(&newState, plan)
+	newState.Timeouts = plan.Timeouts
 
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
 }
 
 func (r *appResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state appResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -536,7 +800,7 @@ func (r *appResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	// Delete existing app
 	err := r.client.AppDelete(state.ID.ValueString())
 
-	if err != nil {
+	if err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting SimpleMDM app",
 			"Could not delete app, unexpected error: "+err.Error(),
@@ -555,7 +819,7 @@ func (r *appResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 	app, err := fetchApp(ctx, r.client, state.ID.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -574,13 +838,22 @@ func (r *appResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 	if !state.BinaryFile.IsNull() {
 		newState.BinaryFile = state.BinaryFile
+		newState.BinarySHA256 = state.BinarySHA256
+		newState.LocalBundleID = state.LocalBundleID
+		newState.LocalVersion = state.LocalVersion
 	}
+	keepAppMunkiSettings(&newState, state)
+	newState.Timeouts = state.Timeouts
 
 	diags = resp.State.Set(ctx, &newState)
 	resp.Diagnostics.Append(diags...)
 }
 
 func (r *appResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	var plan, state appResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -594,6 +867,12 @@ func (r *appResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
+	timeout, diags := plan.Timeouts.Update(ctx, defaultAppProcessingTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	appID := plan.ID.ValueString()
 
 	name := ""
@@ -606,8 +885,14 @@ func (r *appResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		deployTo = plan.DeployTo.ValueString()
 	}
 
-	if !plan.BinaryFile.IsNull() && plan.BinaryFile.ValueString() != "" {
-		err := r.appUpdateWithBinary(ctx, appID, plan.BinaryFile.ValueString(), name, deployTo)
+	settings := appMunkiFields(plan, state)
+
+	switch {
+	case appBinaryChanged(plan, state):
+		uploadCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := r.appUpdateWithBinary(uploadCtx, appID, plan.BinaryFile.ValueString(), name, deployTo, settings...)
+		err = appUploadError(ctx, err, timeout)
+		cancel()
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error updating app",
@@ -615,7 +900,16 @@ func (r *appResource) Update(ctx context.Context, req resource.UpdateRequest, re
 			)
 			return
 		}
-	} else {
+	case len(settings) > 0:
+		err := appUpdateWithForm(ctx, r.client, appID, appUpdateFields(name, deployTo, settings))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating app",
+				"Failed to update app: "+err.Error(),
+			)
+			return
+		}
+	default:
 		_, err := r.client.AppUpdate(
 			appID,
 			name,
@@ -630,18 +924,24 @@ func (r *appResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		}
 	}
 
-	apiApp, err := fetchApp(ctx, r.client, appID)
-	if err != nil {
+	apiApp, err := waitForAppProcessing(ctx, r.client, appID, newAppProcessingWait(timeout))
+	if apiApp == nil {
 		resp.Diagnostics.AddError(
 			"Error reading updated app",
 			"Failed to refresh app state: "+err.Error(),
 		)
 		return
 	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for app processing",
+			"App "+appID+" was updated, but waiting for SimpleMDM to process it failed: "+err.Error(),
+		)
+	}
 
 	newState, diagsFromAPI := newAppResourceModelFromAPI(ctx, apiApp)
 	resp.Diagnostics.Append(diagsFromAPI...)
-	if resp.Diagnostics.HasError() {
+	if diagsFromAPI.HasError() {
 		return
 	}
 
@@ -659,7 +959,17 @@ func (r *appResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	}
 	if !plan.BinaryFile.IsNull() {
 		newState.BinaryFile = plan.BinaryFile
+		newState.BinarySHA256 = plan.BinarySHA256
+		newState.LocalBundleID = plan.LocalBundleID
+		newState.LocalVersion = plan.LocalVersion
+	}
+	if err != nil && appBinaryChanged(plan, state) {
+		// Keep the digest of the previous binary so the next plan uploads
+		// the new binary again instead of settling on a failed one.
+		newState.BinarySHA256 = state.BinarySHA256
 	}
+	keepAppMunkiSettings(&newState, plan)
+	newState.Timeouts = plan.Timeouts
 
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
@@ -101,3 +101,39 @@ func TestNewAppResourceModelFromAPI_PartialData(t *testing.T) {
 		t.Fatalf("expected InstallationChannels to remain null when not provided")
 	}
 }
+
+func TestNewAppResourceModelFromAPI_MunkiSettings(t *testing.T) {
+	ctx := context.Background()
+	response := &appAPIResponse{}
+	response.Data.ID = 7
+	response.Data.Attributes.InstallerType = "copy_from_dmg"
+	response.Data.Attributes.PreinstallScript = "#!/bin/sh\nexit 0"
+	response.Data.Attributes.UninstallMethod = "remove_copied_items"
+	response.Data.Attributes.BlockingApplications = []string{"Safari", "Mail"}
+	response.Data.Attributes.MinimumOSVersion = "13.0"
+
+	model, diags := newAppResourceModelFromAPI(ctx, response)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if model.InstallerType.ValueString() != "copy_from_dmg" {
+		t.Fatalf("expected InstallerType to be set")
+	}
+	if model.PreinstallScript.ValueString() != "#!/bin/sh\nexit 0" {
+		t.Fatalf("expected PreinstallScript to be set")
+	}
+	if !model.PostinstallScript.IsNull() || !model.UninstallScript.IsNull() {
+		t.Fatalf("expected scripts that are not reported to remain null")
+	}
+	if model.UninstallMethod.ValueString() != "remove_copied_items" {
+		t.Fatalf("expected UninstallMethod to be set")
+	}
+	if model.MinimumOSVersion.ValueString() != "13.0" {
+		t.Fatalf("expected MinimumOSVersion to be set")
+	}
+	values := model.BlockingApplications.Elements()
+	if len(values) != 2 || values[1].(types.String).ValueString() != "Mail" {
+		t.Fatalf("unexpected blocking applications: %v", values)
+	}
+}
@@ -1,12 +1,25 @@
 package provider
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	simplemdm "github.com/DavidKrau/simplemdm-go-client"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 func testAccCheckAppDestroy(s *terraform.State) error {
@@ -93,3 +106,237 @@ func TestAccAppResourceWithBundleIdAttr(t *testing.T) {
 		},
 	})
 }
+
+func TestAccAppResourceWithBinaryFile(t *testing.T) {
+	testAccPreCheck(t)
+	testAccSkipUnlessFakeAPI(t, "the uploaded binary is not a signed app")
+
+	binaryPath := filepath.Join(t.TempDir(), "InternalTools.ipa")
+	digests := map[string]string{}
+	writeBinary := func(bundleID, version string) func() {
+		return func() {
+			content := testAppArchive(t, bundleID, version)
+			if err := os.WriteFile(binaryPath, content, 0o644); err != nil {
+				t.Fatalf("writing app binary: %v", err)
+			}
+			sum := sha256.Sum256(content)
+			digests[version] = hex.EncodeToString(sum[:])
+		}
+	}
+	checkDigest := func(version string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			return resource.TestCheckResourceAttr("simplemdm_app.testapp", "binary_sha256", digests[version])(s)
+		}
+	}
+
+	config := providerConfig + fmt.Sprintf(`
+				resource "simplemdm_app" "testapp" {
+					name        = "Internal Tools"
+					binary_file = %q
+
+					timeouts {
+						create = "2m"
+						update = "2m"
+					}
+				}
+				`, binaryPath)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckAppDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: writeBinary("com.example.internaltools", "1.0"),
+				Config:    config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkDigest("1.0"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "local_bundle_id", "com.example.internaltools"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "local_version", "1.0"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "bundle_id", "com.example.internaltools"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "version", "1.0"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "processing_status", "processed"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "timeouts.create", "2m"),
+					resource.TestCheckResourceAttrSet("simplemdm_app.testapp", "id"),
+				),
+			},
+			// Rebuilding the binary at the same path uploads it again in place.
+			{
+				PreConfig: writeBinary("com.example.internaltools", "1.1"),
+				Config:    config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("simplemdm_app.testapp", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("simplemdm_app.testapp", tfjsonpath.New("local_version"), knownvalue.StringExact("1.1")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkDigest("1.1"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "local_version", "1.1"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "version", "1.1"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "processing_status", "processed"),
+				),
+			},
+			// A binary of another app is rejected before it is uploaded.
+			{
+				PreConfig:   writeBinary("com.example.otherapp", "2.0"),
+				Config:      config,
+				ExpectError: regexp.MustCompile(`App binary bundle identifier changed`),
+			},
+		},
+	})
+}
+
+func TestAccAppResourceMunkiSettings(t *testing.T) {
+	testAccPreCheck(t)
+	testAccSkipUnlessFakeAPI(t, "the uploaded binary is not a signed app")
+
+	binaryPath := filepath.Join(t.TempDir(), "InternalTools.pkg")
+	if err := os.WriteFile(binaryPath, testFlatPackage(t, "com.example.munkitools", "1.0"), 0o644); err != nil {
+		t.Fatalf("writing app binary: %v", err)
+	}
+
+	config := func(settings string) string {
+		return providerConfig + fmt.Sprintf(`
+				resource "simplemdm_app" "testapp" {
+					binary_file = %q
+					%s
+				}
+				`, binaryPath, settings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckAppDestroy,
+		Steps: []resource.TestStep{
+			// Invalid settings are rejected when planning.
+			{
+				Config:      config(`installer_type = "dmg"`),
+				ExpectError: regexp.MustCompile(`installer_type value must be one of`),
+			},
+			{
+				Config:      config(`minimum_os_version = "Sonoma"`),
+				ExpectError: regexp.MustCompile(`must be a macOS version`),
+			},
+			{
+				Config: providerConfig + `
+				resource "simplemdm_app" "storeapp" {
+					app_store_id     = "284882215"
+					uninstall_method = "remove_app"
+				}
+				`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config: config(`
+					installer_type        = "copy_from_dmg"
+					preinstall_script     = "#!/bin/sh\necho before"
+					uninstall_method      = "uninstall_script"
+					uninstall_script      = "#!/bin/sh\nrm -rf /Applications/Tools.app"
+					blocking_applications = ["Safari", "Mail"]
+					minimum_os_version    = "13.0"
+				`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "local_bundle_id", "com.example.munkitools"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "installer_type", "copy_from_dmg"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "preinstall_script", "#!/bin/sh\necho before"),
+					resource.TestCheckNoResourceAttr("simplemdm_app.testapp", "postinstall_script"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "uninstall_method", "uninstall_script"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "blocking_applications.#", "2"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "blocking_applications.1", "Mail"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "minimum_os_version", "13.0"),
+				),
+			},
+			// Empty values clear scripts and lists without planning again.
+			{
+				Config: config(`
+					installer_type        = "copy_from_dmg"
+					preinstall_script     = ""
+					postinstall_script    = "#!/bin/sh\necho after"
+					uninstall_method      = "remove_copied_items"
+					uninstall_script      = ""
+					blocking_applications = []
+					minimum_os_version    = "14.2.1"
+				`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("simplemdm_app.testapp", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "preinstall_script", ""),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "postinstall_script", "#!/bin/sh\necho after"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "uninstall_method", "remove_copied_items"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "blocking_applications.#", "0"),
+					resource.TestCheckResourceAttr("simplemdm_app.testapp", "minimum_os_version", "14.2.1"),
+				),
+			},
+			{
+				ResourceName:            "simplemdm_app.testapp",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"binary_file", "binary_sha256", "local_bundle_id", "local_version", "timeouts", "preinstall_script", "uninstall_script", "blocking_applications"},
+			},
+		},
+	})
+}
+
+// testAppArchive returns a minimal .ipa holding an Info.plist with the given
+// bundle identifier and version.
+func testAppArchive(t *testing.T, bundleID, version string) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	plist, err := writer.Create("Payload/InternalTools.app/Info.plist")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = fmt.Fprintf(plist, `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>CFBundleIdentifier</key>
+	<string>%s</string>
+	<key>CFBundleShortVersionString</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, bundleID, version)
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// testFlatPackage returns a minimal flat component package (.pkg), a XAR
+// archive holding a PackageInfo with the given identifier and version.
+func testFlatPackage(t *testing.T, identifier, version string) []byte {
+	t.Helper()
+
+	packageInfo := fmt.Sprintf(`<pkg-info format-version="2" identifier="%s" version="%s" install-location="/"/>`, identifier, version)
+	compressedInfo := &bytes.Buffer{}
+	w := zlib.NewWriter(compressedInfo)
+	_, _ = w.Write([]byte(packageInfo))
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	toc := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?><xar><toc><file id="1"><name>PackageInfo</name><type>file</type><data><offset>0</offset><length>%d</length><size>%d</size><encoding style="application/x-gzip"/></data></file></toc></xar>`,
+		compressedInfo.Len(), len(packageInfo))
+	compressedTOC := &bytes.Buffer{}
+	w = zlib.NewWriter(compressedTOC)
+	_, _ = w.Write([]byte(toc))
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const headerSize = 28
+	header := make([]byte, headerSize)
+	copy(header, "xar!")
+	binary.BigEndian.PutUint16(header[4:], headerSize)
+	binary.BigEndian.PutUint16(header[6:], 1)
+	binary.BigEndian.PutUint64(header[8:], uint64(compressedTOC.Len()))
+	binary.BigEndian.PutUint64(header[16:], uint64(len(toc)))
+
+	return append(append(header, compressedTOC.Bytes()...), compressedInfo.Bytes()...)
+}
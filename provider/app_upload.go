@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// appUploadProgressSteps is the number of progress entries logged for an
+// upload of known size.
+const appUploadProgressSteps = 10
+
+// appUploadField is a form field sent along with an app binary.
+type appUploadField struct {
+	name  string
+	value string
+}
+
+// appUpload streams an app binary and its form fields as a multipart body.
+// The body is produced on the fly through a pipe, so binaries of several
+// gigabytes are never held in memory.
+type appUpload struct {
+	binaryPath string
+	fields     []appUploadField
+	boundary   string
+}
+
+// newAppUploadRequest builds a request uploading the binary at binaryPath.
+// The Content-Length is set when the size of the file is known, and GetBody
+// opens the file again so the request can be retried. The request is exempt
+// from the client's per-attempt timeout, so ctx must bound the upload.
+func newAppUploadRequest(ctx context.Context, method, url, binaryPath string, fields ...appUploadField) (*http.Request, error) {
+	ctx = simplemdmext.WithoutAttemptTimeout(ctx)
+
+	info, err := os.Stat(binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open app binary %q: %w", binaryPath, err)
+	}
+
+	upload := &appUpload{
+		binaryPath: binaryPath,
+		fields:     fields,
+		boundary:   multipart.NewWriter(io.Discard).Boundary(),
+	}
+
+	var size int64 = -1
+	if info.Mode().IsRegular() {
+		size = info.Size()
+	}
+
+	body, err := upload.open(ctx, size)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		_ = body.Close()
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+upload.boundary)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return upload.open(ctx, size)
+	}
+
+	req.ContentLength = -1
+	if size >= 0 {
+		overhead, err := upload.overhead()
+		if err != nil {
+			_ = body.Close()
+			return nil, err
+		}
+		req.ContentLength = overhead + size
+	}
+
+	return req, nil
+}
+
+// appUploadError explains an upload that was cut short by the create or update
+// timeout of the resource, rather than by ctx itself.
+func appUploadError(ctx context.Context, err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("the upload did not finish within %s: %w", timeout, err)
+	}
+
+	return err
+}
+
+// open starts writing the multipart body into a pipe from a freshly opened
+// file and returns the reading end.
+func (u *appUpload) open(ctx context.Context, size int64) (io.ReadCloser, error) {
+	file, err := os.Open(u.binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open app binary %q: %w", u.binaryPath, err)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		content := &appUploadProgress{ctx: ctx, reader: file, binaryPath: u.binaryPath, total: size}
+		err := u.write(writer, content)
+		if cerr := file.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("unable to close app binary %q: %w", u.binaryPath, cerr)
+		}
+		_ = writer.CloseWithError(err)
+	}()
+
+	return reader, nil
+}
+
+// overhead returns the size of the multipart framing and fields, that is the
+// body size less the binary itself.
+func (u *appUpload) overhead() (int64, error) {
+	counter := &byteCounter{}
+	if err := u.write(counter, http.NoBody); err != nil {
+		return 0, err
+	}
+
+	return counter.n, nil
+}
+
+func (u *appUpload) write(w io.Writer, content io.Reader) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(u.boundary); err != nil {
+		return err
+	}
+
+	part, err := writer.CreateFormFile("binary", filepath.Base(u.binaryPath))
+	if err != nil {
+		return fmt.Errorf("unable to create app binary form data: %w", err)
+	}
+
+	if _, err := io.Copy(part, content); err != nil {
+		return fmt.Errorf("unable to read app binary %q: %w", u.binaryPath, err)
+	}
+
+	for _, field := range u.fields {
+		if err := writer.WriteField(field.name, field.value); err != nil {
+			return fmt.Errorf("unable to encode app %s: %w", field.name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("unable to finalize app upload payload: %w", err)
+	}
+
+	return nil
+}
+
+type byteCounter struct {
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// appUploadProgress logs how much of an app binary has been sent, every
+// tenth of the file when its size is known.
+type appUploadProgress struct {
+	ctx        context.Context
+	reader     io.Reader
+	binaryPath string
+	total      int64
+	read       int64
+	logged     int64
+}
+
+func (p *appUploadProgress) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.read += int64(n)
+
+	if p.total > 0 {
+		step := p.read * appUploadProgressSteps / p.total
+		if step > p.logged || (err == io.EOF && p.logged < appUploadProgressSteps) {
+			p.logged = step
+			tflog.Debug(p.ctx, "Uploading app binary", map[string]any{
+				"app_binary":     p.binaryPath,
+				"uploaded_bytes": p.read,
+				"total_bytes":    p.total,
+			})
+		}
+	} else if err == io.EOF {
+		tflog.Debug(p.ctx, "Uploaded app binary", map[string]any{
+			"app_binary":     p.binaryPath,
+			"uploaded_bytes": p.read,
+		})
+	}
+
+	return n, err
+}
@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+)
+
+func TestAppUploadStreamsWithContentLengthAndRetries(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "tools.pkg")
+	content := strings.Repeat("pkg-bytes", 100000)
+	if err := os.WriteFile(binaryPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type upload struct {
+		contentLength int64
+		received      int
+		binary        string
+		name          string
+		deployTo      string
+	}
+	var (
+		mu      sync.Mutex
+		uploads []upload
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(raw)))
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("unexpected multipart error: %v", err)
+		}
+
+		current := upload{
+			contentLength: r.ContentLength,
+			received:      len(raw),
+			name:          r.FormValue("name"),
+			deployTo:      r.FormValue("deploy_to"),
+		}
+		if file, _, err := r.FormFile("binary"); err == nil {
+			binary, _ := io.ReadAll(file)
+			current.binary = string(binary)
+		}
+
+		mu.Lock()
+		uploads = append(uploads, current)
+		attempt := len(uploads)
+		mu.Unlock()
+
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":1}}`))
+	}))
+	defer server.Close()
+
+	endpoint, err := simplemdmext.ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	options := simplemdmext.DefaultClientOptions()
+	options.Endpoint = endpoint
+	options.RetryMinWait = time.Millisecond
+	options.RetryMaxWait = time.Millisecond
+	client, err := simplemdmext.NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := &appResource{client: client}
+	if err := r.appUpdateWithBinary(context.Background(), "1", binaryPath, "Tools", "all"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(uploads) != 2 {
+		t.Fatalf("got %d uploads, want a retried second attempt", len(uploads))
+	}
+	for i, upload := range uploads {
+		if upload.contentLength <= int64(len(content)) || upload.contentLength != int64(upload.received) {
+			t.Errorf("attempt %d: Content-Length %d, received %d bytes", i+1, upload.contentLength, upload.received)
+		}
+		if upload.binary != content || upload.name != "Tools" || upload.deployTo != "all" {
+			t.Errorf("attempt %d: unexpected form: name %q, deploy_to %q, %d binary bytes", i+1, upload.name, upload.deployTo, len(upload.binary))
+		}
+	}
+}
+
+// newAppUploadTestClient returns a client for endpoint, or a.simplemdm.com
+// when empty, that sends its requests through transport.
+func newAppUploadTestClient(t *testing.T, endpoint string, transport http.RoundTripper) *simplemdm.Client {
+	t.Helper()
+
+	options := simplemdmext.DefaultClientOptions()
+	options.Transport = transport
+	if endpoint != "" {
+		parsed, err := simplemdmext.ParseEndpoint(endpoint)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		options.Endpoint = parsed
+	}
+
+	client, err := simplemdmext.NewClient("a.simplemdm.com", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return client
+}
+
+func TestAppCreateWithBinaryReplaysFromCassette(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "tools.pkg")
+	if err := os.WriteFile(binaryPath, testFlatPackage(t, "com.example.tools", "1.0"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var uploads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploads++
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"type":"app","id":5,"attributes":{"name":"Tools"}}}`))
+	}))
+	defer server.Close()
+
+	cassettePath := filepath.Join(t.TempDir(), "upload.json")
+	recorder := simplemdmext.NewCassette(cassettePath, nil, "key")
+	r := &appResource{client: newAppUploadTestClient(t, server.URL, recorder)}
+	if _, err := r.appCreateWithBinary(context.Background(), binaryPath, "Tools", appUploadField{name: "installer_type", value: "package"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	player, err := simplemdmext.LoadCassette(cassettePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r = &appResource{client: newAppUploadTestClient(t, "", player)}
+
+	// The replayed upload uses a new multipart boundary.
+	app, err := r.appCreateWithBinary(context.Background(), binaryPath, "Tools", appUploadField{name: "installer_type", value: "package"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Data.ID != 5 || uploads != 1 {
+		t.Fatalf("unexpected replay: app %d, %d uploads reached the server", app.Data.ID, uploads)
+	}
+
+	if err := os.WriteFile(binaryPath, testFlatPackage(t, "com.example.tools", "2.0"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.appCreateWithBinary(context.Background(), binaryPath, "Tools", appUploadField{name: "installer_type", value: "package"}); err == nil {
+		t.Fatal("expected a different binary not to match the recording")
+	}
+}
+
+func TestNewAppUploadRequestReportsMissingFile(t *testing.T) {
+	_, err := newAppUploadRequest(context.Background(), http.MethodPost, "https://example.com/api/v1/apps", filepath.Join(t.TempDir(), "missing.pkg"))
+	if err == nil || !strings.Contains(err.Error(), "unable to open app binary") {
+		t.Fatalf("got %v, want an open error", err)
+	}
+}
+
+func TestAppUploadIsBoundedByResourceTimeout(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "tools.pkg")
+	if err := os.WriteFile(binaryPath, []byte("pkg-bytes"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	endpoint, err := simplemdmext.ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	options := simplemdmext.DefaultClientOptions()
+	options.Endpoint = endpoint
+	client, err := simplemdmext.NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	uploadCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+
+	r := &appResource{client: client}
+	err = appUploadError(ctx, r.appUpdateWithBinary(uploadCtx, "1", binaryPath, "", ""), 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "the upload did not finish within 20ms") {
+		t.Fatalf("got %v, want the upload timeout", err)
+	}
+}
@@ -2,12 +2,12 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -133,7 +133,7 @@ func (d *appsDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		entry := appsDataSourceAppModel{
 			ID:                   types.StringValue(strconv.Itoa(app.ID)),
 			Name:                 types.StringValue(app.Attributes.Name),
-			AppStoreID:           stringValueOrNull(app.Attributes.AppStoreID),
+			AppStoreID:           appStoreIDValue(app.Attributes.AppStoreID),
 			BundleID:             stringValueOrNull(app.Attributes.BundleIdentifier),
 			AppType:              stringValueOrNull(app.Attributes.AppType),
 			Version:              stringValueOrNull(app.Attributes.Version),
@@ -156,7 +156,7 @@ func (d *appsDataSource) Configure(_ context.Context, req datasource.ConfigureRe
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -166,59 +166,19 @@ func (d *appsDataSource) Configure(_ context.Context, req datasource.ConfigureRe
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 // fetchAllApps retrieves all apps with pagination support
 func fetchAllApps(ctx context.Context, client *simplemdm.Client, includeShared types.Bool) ([]appData, error) {
-	var allApps []appData
-	startingAfter := 0
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/apps?limit=%d", client.HostName, limit)
-		if startingAfter > 0 {
-			url += fmt.Sprintf("&starting_after=%d", startingAfter)
-		}
-		if !includeShared.IsNull() && includeShared.ValueBool() {
-			url += "&include_shared=true"
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		body, err := client.RequestResponse200(req)
-		if err != nil {
-			return nil, err
-		}
-
-		var response appsAPIResponse
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, err
-		}
-
-		allApps = append(allApps, response.Data...)
-
-		if !response.HasMore {
-			break
-		}
-
-		if len(response.Data) > 0 {
-			startingAfter = response.Data[len(response.Data)-1].ID
-		} else {
-			break
-		}
+	query := url.Values{}
+	if !includeShared.IsNull() && includeShared.ValueBool() {
+		query.Set("include_shared", "true")
 	}
 
-	return allApps, nil
-}
-
-// appsAPIResponse represents the paginated API response for apps list
-type appsAPIResponse struct {
-	Data    []appData `json:"data"`
-	HasMore bool      `json:"has_more"`
+	return simplemdmext.ListAll(ctx, client, "apps", simplemdmext.ListOptions{Query: query}, simplemdmext.IntCursor(func(app appData) int {
+		return app.ID
+	}))
 }
 
 // appData represents a single app in the list response
@@ -230,7 +190,7 @@ type appData struct {
 
 type appAttributes struct {
 	Name                 string   `json:"name"`
-	AppStoreID           string   `json:"itunes_store_id"`
+	AppStoreID           *int     `json:"itunes_store_id"`
 	BundleIdentifier     string   `json:"bundle_identifier"`
 	AppType              string   `json:"app_type"`
 	Version              string   `json:"version"`
@@ -238,3 +198,13 @@ type appAttributes struct {
 	ProcessingStatus     string   `json:"processing_status"`
 	InstallationChannels []string `json:"installation_channels"`
 }
+
+// appStoreIDValue converts the numeric iTunes store ID the API returns into
+// the string the schema exposes.
+func appStoreIDValue(id *int) types.String {
+	if id == nil {
+		return types.StringNull()
+	}
+
+	return types.StringValue(strconv.Itoa(*id))
+}
@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &assignmentGroupAppResource{}
+	_ resource.ResourceWithConfigure   = &assignmentGroupAppResource{}
+	_ resource.ResourceWithImportState = &assignmentGroupAppResource{}
+)
+
+// assignmentGroupAppInstallTypes lists the per-app install types SimpleMDM
+// accepts when assigning an app.
+var assignmentGroupAppInstallTypes = []string{"managed", "self_serve", "managed_updates", "default_installs"}
+
+// assignmentGroupAppResourceModel maps the resource schema data.
+type assignmentGroupAppResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	GroupID        types.String `tfsdk:"group_id"`
+	AppID          types.String `tfsdk:"app_id"`
+	InstallType    types.String `tfsdk:"install_type"`
+	DeployOnCreate types.Bool   `tfsdk:"deploy_on_create"`
+}
+
+// AssignmentGroupAppResource is a helper function to simplify the provider implementation.
+func AssignmentGroupAppResource() resource.Resource {
+	return &assignmentGroupAppResource{}
+}
+
+// assignmentGroupAppResource assigns a single app to an assignment group.
+type assignmentGroupAppResource struct {
+	client *simplemdm.Client
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *assignmentGroupAppResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*providerData).client
+}
+
+// Metadata returns the resource type name.
+func (r *assignmentGroupAppResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_assignmentgroup_app"
+}
+
+// Schema defines the schema for the resource.
+func (r *assignmentGroupAppResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Assigns an App to an Assignment Group with its own install type. Use it for groups that do not list the app in the apps attribute of simplemdm_assignmentgroup; managing the same app both ways makes the two resources undo each other.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Identifier of the assignment in the form group_id:app_id.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"group_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the Assignment Group the app is assigned to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"app_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the App to assign.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"install_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Optional. The install type of the app in munki assignment groups. Must be one of managed, self_serve, managed_updates or default_installs. SimpleMDM defaults to managed and ignores it for standard groups. Changing it assigns the app again without removing it first.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(assignmentGroupAppInstallTypes...),
+				},
+			},
+			"deploy_on_create": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Optional. Set true to send a Push Apps command to the group once the app has been assigned. Only applies when the assignment is created. Defaults to false.",
+			},
+		},
+	}
+}
+
+// Import function
+func (r *assignmentGroupAppResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	groupID, appID, ok := strings.Cut(req.ID, ":")
+	if !ok || groupID == "" || appID == "" || strings.Contains(appID, ":") {
+		resp.Diagnostics.AddError(
+			"Unexpected import identifier format",
+			"Expected group_id:app_id, got "+req.ID,
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_id"), groupID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_id"), appID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deploy_on_create"), false)...)
+}
+
+// Create a new resource
+func (r *assignmentGroupAppResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
+	var plan assignmentGroupAppResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	groupID, appID := plan.GroupID.ValueString(), plan.AppID.ValueString()
+	if err := assignmentGroupAssignApp(ctx, r.client, groupID, appID, plan.InstallType.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Error assigning app to assignment group",
+			"Could not assign app "+appID+" to assignment group "+groupID+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(buildAssignmentGroupAppID(groupID, appID))
+
+	// The assignment exists from here on, so it is saved even when the
+	// deployment could not be requested.
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.DeployOnCreate.ValueBool() {
+		if err := r.client.AssignmentGroupPushApps(groupID); err != nil {
+			resp.Diagnostics.AddError(
+				"Error when sending command to Push Apps",
+				"App "+appID+" was assigned to assignment group "+groupID+", but the Push Apps command failed: "+err.Error(),
+			)
+		}
+	}
+}
+
+// Read resource information
+func (r *assignmentGroupAppResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state assignmentGroupAppResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	assignmentGroup, err := fetchAssignmentGroup(ctx, r.client, state.GroupID.ValueString())
+	if err != nil {
+		if isNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading SimpleMDM assignment group",
+			"Could not read assignment group ID "+state.GroupID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	assigned := slices.ContainsFunc(assignmentGroup.Data.Relationships.Apps.Data, func(item assignmentGroupRelationshipItem) bool {
+		return strconv.Itoa(item.ID) == state.AppID.ValueString()
+	})
+	if !assigned {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// The API does not report the install type of an assignment, so the one
+	// in state is kept.
+	state.ID = types.StringValue(buildAssignmentGroupAppID(state.GroupID.ValueString(), state.AppID.ValueString()))
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update changes the install type by assigning the app again.
+func (r *assignmentGroupAppResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
+	var plan, state assignmentGroupAppResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.InstallType.Equal(state.InstallType) {
+		groupID, appID := plan.GroupID.ValueString(), plan.AppID.ValueString()
+		if err := assignmentGroupAssignApp(ctx, r.client, groupID, appID, plan.InstallType.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating app assignment",
+				"Could not change the install type of app "+appID+" in assignment group "+groupID+", unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the app from the assignment group.
+func (r *assignmentGroupAppResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
+	var state assignmentGroupAppResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.AssignmentGroupUnAssignObject(state.GroupID.ValueString(), state.AppID.ValueString(), "apps")
+	if err != nil && !isNotFoundError(err) {
+		resp.Diagnostics.AddError(
+			"Error removing app from assignment group",
+			"Could not remove app "+state.AppID.ValueString()+" from assignment group "+state.GroupID.ValueString()+", unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// assignmentGroupAssignApp assigns an app to an assignment group, sending
+// installType when set. Assigning an app that is already assigned updates
+// its install type.
+func assignmentGroupAssignApp(ctx context.Context, client *simplemdm.Client, groupID, appID, installType string) error {
+	url := simplemdmext.APIURL(client, "assignment_groups/%s/apps/%s", groupID, appID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+
+	if installType != "" {
+		q := req.URL.Query()
+		q.Add("install_type", installType)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	_, err = simplemdmext.Do(client, req, http.StatusNoContent)
+	return err
+}
+
+func buildAssignmentGroupAppID(groupID, appID string) string {
+	return fmt.Sprintf("%s:%s", groupID, appID)
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testAccAssignmentGroupAppConfig(appID, installType string) string {
+	return providerConfig + fmt.Sprintf(`
+		resource "simplemdm_assignmentgroup" "test" {
+			name = "Per-app Install Types"
+		}
+
+		resource "simplemdm_assignmentgroup_app" "test" {
+			group_id         = simplemdm_assignmentgroup.test.id
+			app_id           = %q
+			install_type     = %q
+			deploy_on_create = true
+		}
+	`, appID, installType)
+}
+
+// testAccCheckAssignmentGroupHasApp verifies whether the group of the named
+// resource lists the app.
+func testAccCheckAssignmentGroupHasApp(name, appID string, want bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+
+		client, err := getTestClient()
+		if err != nil {
+			return fmt.Errorf("failed to create test client: %w", err)
+		}
+
+		group, err := fetchAssignmentGroup(context.Background(), client, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, app := range group.Data.Relationships.Apps.Data {
+			if strconv.Itoa(app.ID) == appID {
+				if !want {
+					return fmt.Errorf("app %s is still assigned to assignment group %s", appID, rs.Primary.ID)
+				}
+				return nil
+			}
+		}
+		if want {
+			return fmt.Errorf("app %s is not assigned to assignment group %s", appID, rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func TestAccAssignmentGroupAppResource(t *testing.T) {
+	testAccPreCheck(t)
+
+	appID := testAccRequireEnv(t, "SIMPLEMDM_APP_ID")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckAssignmentGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAssignmentGroupAppConfig(appID, "self_serve"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("simplemdm_assignmentgroup_app.test", "group_id", "simplemdm_assignmentgroup.test", "id"),
+					resource.TestCheckResourceAttr("simplemdm_assignmentgroup_app.test", "app_id", appID),
+					resource.TestCheckResourceAttr("simplemdm_assignmentgroup_app.test", "install_type", "self_serve"),
+					resource.TestCheckResourceAttr("simplemdm_assignmentgroup_app.test", "deploy_on_create", "true"),
+					testAccCheckAssignmentGroupHasApp("simplemdm_assignmentgroup.test", appID, true),
+				),
+			},
+			// The group picks the app up on refresh without planning to
+			// remove it, and a new install type assigns the app in place.
+			{
+				Config: testAccAssignmentGroupAppConfig(appID, "managed_updates"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("simplemdm_assignmentgroup.test", plancheck.ResourceActionNoop),
+						plancheck.ExpectResourceAction("simplemdm_assignmentgroup_app.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("simplemdm_assignmentgroup_app.test", "install_type", "managed_updates"),
+					resource.TestCheckTypeSetElemAttr("simplemdm_assignmentgroup.test", "apps.*", appID),
+				),
+			},
+			{
+				ResourceName: "simplemdm_assignmentgroup_app.test",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return s.RootModule().Resources["simplemdm_assignmentgroup_app.test"].Primary.ID, nil
+				},
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"install_type", "deploy_on_create"},
+			},
+			{
+				ResourceName:  "simplemdm_assignmentgroup_app.test",
+				ImportState:   true,
+				ImportStateId: "not-a-pair",
+				ExpectError:   regexp.MustCompile(`Expected group_id:app_id`),
+			},
+			// Removing the resource unassigns the app and leaves the group.
+			{
+				Config: providerConfig + `
+					resource "simplemdm_assignmentgroup" "test" {
+						name = "Per-app Install Types"
+					}
+				`,
+				Check: testAccCheckAssignmentGroupHasApp("simplemdm_assignmentgroup.test", appID, false),
+			},
+		},
+	})
+}
@@ -122,7 +122,7 @@ func (d *assignmentGroupDataSource) Configure(_ context.Context, req datasource.
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -131,7 +131,7 @@ func (d *assignmentGroupDataSource) Configure(_ context.Context, req datasource.
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 func (d *assignmentGroupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
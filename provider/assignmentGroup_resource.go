@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"strconv"
-	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
@@ -65,7 +64,7 @@ func (r *assignment_groupResource) Configure(_ context.Context, req resource.Con
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -213,6 +212,10 @@ func (r *assignment_groupResource) ImportState(ctx context.Context, req resource
 
 // Create a new resource
 func (r *assignment_groupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan assignment_groupResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -381,7 +384,7 @@ func (r *assignment_groupResource) Read(ctx context.Context, req resource.ReadRe
 	// Get refreshed assignment group values from SimpleMDM
 	assignmentGroup, err := fetchAssignmentGroup(ctx, r.client, state.ID.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -404,6 +407,10 @@ func (r *assignment_groupResource) Read(ctx context.Context, req resource.ReadRe
 
 // update group
 func (r *assignment_groupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan, state assignment_groupResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -535,6 +542,10 @@ func (r *assignment_groupResource) Update(ctx context.Context, req resource.Upda
 
 // Delete group
 func (r *assignment_groupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state assignment_groupResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -544,7 +555,7 @@ func (r *assignment_groupResource) Delete(ctx context.Context, req resource.Dele
 
 	// Delete existing assignment group
 	err := r.client.AssignmentGroupDelete(state.ID.ValueString())
-	if err != nil {
+	if err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting SimpleMDM assignment group",
 			"Could not delete assignment group, unexpected error: "+err.Error(),
@@ -115,8 +115,9 @@ func TestAccAssignmentGroupResource(t *testing.T) {
 					resource.TestCheckResourceAttrSet("simplemdm_assignmentgroup.testgroup2", "id"),
 					// Note: created_at and updated_at may not be immediately returned by API
 				),
-				// Allow non-empty plan due to API eventual consistency with relationships
-				ExpectNonEmptyPlan: true,
+				// Allow non-empty plan due to API eventual consistency with relationships.
+				// The fake API is consistent, so the plan must be empty there.
+				ExpectNonEmptyPlan: testAccEventuallyConsistent(),
 			},
 			// ImportState testing
 			{
@@ -2,12 +2,11 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strconv"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -136,7 +135,7 @@ func (d *assignmentGroupsDataSource) Configure(_ context.Context, req datasource
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -146,56 +145,14 @@ func (d *assignmentGroupsDataSource) Configure(_ context.Context, req datasource
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 // fetchAllAssignmentGroups retrieves all assignment groups with pagination support
 func fetchAllAssignmentGroups(ctx context.Context, client *simplemdm.Client) ([]assignmentGroupData, error) {
-	var allGroups []assignmentGroupData
-	startingAfter := 0
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/assignment_groups?limit=%d", client.HostName, limit)
-		if startingAfter > 0 {
-			url += fmt.Sprintf("&starting_after=%d", startingAfter)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		body, err := client.RequestResponse200(req)
-		if err != nil {
-			return nil, err
-		}
-
-		var response assignmentGroupsAPIResponse
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, err
-		}
-
-		allGroups = append(allGroups, response.Data...)
-
-		if !response.HasMore {
-			break
-		}
-
-		if len(response.Data) > 0 {
-			startingAfter = response.Data[len(response.Data)-1].ID
-		} else {
-			break
-		}
-	}
-
-	return allGroups, nil
-}
-
-// assignmentGroupsAPIResponse represents the paginated API response for assignment groups list
-type assignmentGroupsAPIResponse struct {
-	Data    []assignmentGroupData `json:"data"`
-	HasMore bool                  `json:"has_more"`
+	return simplemdmext.ListAll(ctx, client, "assignment_groups", simplemdmext.ListOptions{}, simplemdmext.IntCursor(func(group assignmentGroupData) int {
+		return group.ID
+	}))
 }
 
 // assignmentGroupData represents a single assignment group in the list response
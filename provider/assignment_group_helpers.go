@@ -3,12 +3,12 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -52,14 +52,14 @@ type assignmentGroupRelationshipItem struct {
 }
 
 func fetchAssignmentGroup(ctx context.Context, client *simplemdm.Client, id string) (*assignmentGroupResponse, error) {
-	url := fmt.Sprintf("https://%s/api/v1/assignment_groups/%s", client.HostName, id)
+	url := simplemdmext.APIURL(client, "assignment_groups/%s", id)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := client.RequestResponse200(req)
+	body, err := simplemdmext.Do(client, req, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +93,7 @@ type assignmentGroupUpsertRequest struct {
 }
 
 func createAssignmentGroup(ctx context.Context, client *simplemdm.Client, payload assignmentGroupUpsertRequest) (*assignmentGroupResponse, error) {
-	url := fmt.Sprintf("https://%s/api/v1/assignment_groups", client.HostName)
+	url := simplemdmext.APIURL(client, "assignment_groups")
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
@@ -102,7 +102,7 @@ func createAssignmentGroup(ctx context.Context, client *simplemdm.Client, payloa
 
 	req.URL.RawQuery = buildAssignmentGroupQuery(payload, true).Encode()
 
-	body, err := client.RequestResponse201(req)
+	body, err := simplemdmext.Do(client, req, http.StatusCreated)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +116,7 @@ func createAssignmentGroup(ctx context.Context, client *simplemdm.Client, payloa
 }
 
 func updateAssignmentGroup(ctx context.Context, client *simplemdm.Client, id string, payload assignmentGroupUpsertRequest) error {
-	url := fmt.Sprintf("https://%s/api/v1/assignment_groups/%s", client.HostName, id)
+	url := simplemdmext.APIURL(client, "assignment_groups/%s", id)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, nil)
 	if err != nil {
@@ -125,7 +125,7 @@ func updateAssignmentGroup(ctx context.Context, client *simplemdm.Client, id str
 
 	req.URL.RawQuery = buildAssignmentGroupQuery(payload, false).Encode()
 
-	_, err = client.RequestResponse204(req)
+	_, err = simplemdmext.Do(client, req, http.StatusNoContent)
 	return err
 }
 
@@ -167,7 +167,7 @@ func setOptionalString(values url.Values, key string, value *string) {
 }
 
 func assignmentGroupAssignDevice(ctx context.Context, client *simplemdm.Client, groupID string, deviceID string, removeOthers bool) error {
-	url := fmt.Sprintf("https://%s/api/v1/assignment_groups/%s/devices/%s", client.HostName, groupID, deviceID)
+	url := simplemdmext.APIURL(client, "assignment_groups/%s/devices/%s", groupID, deviceID)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
@@ -180,7 +180,7 @@ func assignmentGroupAssignDevice(ctx context.Context, client *simplemdm.Client,
 		req.URL.RawQuery = q.Encode()
 	}
 
-	_, err = client.RequestResponse204(req)
+	_, err = simplemdmext.Do(client, req, http.StatusNoContent)
 	return err
 }
 
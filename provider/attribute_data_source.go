@@ -95,7 +95,7 @@ func (d *attributeDataSource) Configure(_ context.Context, req datasource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -105,5 +105,5 @@ func (d *attributeDataSource) Configure(_ context.Context, req datasource.Config
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -43,7 +42,7 @@ func (r *attributeResource) Configure(_ context.Context, req resource.ConfigureR
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -86,6 +85,10 @@ func (r *attributeResource) ImportState(ctx context.Context, req resource.Import
 
 // Create a new resource
 func (r *attributeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan attributeResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -127,7 +130,7 @@ func (r *attributeResource) Read(ctx context.Context, req resource.ReadRequest,
 	// Get refreshed attribute value from SimpleMDM
 	attribute, err := r.client.AttributeGet(state.Name.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -153,6 +156,10 @@ func (r *attributeResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *attributeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan attributeResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -179,6 +186,10 @@ func (r *attributeResource) Update(ctx context.Context, req resource.UpdateReque
 }
 
 func (r *attributeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state attributeResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -188,7 +199,7 @@ func (r *attributeResource) Delete(ctx context.Context, req resource.DeleteReque
 
 	// Delete existing attribute
 	err := r.client.AttributeDelete(state.Name.ValueString())
-	if err != nil {
+	if err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting SimpleMDM attribute",
 			"Could not delete attribute, unexpected error: "+err.Error(),
@@ -2,11 +2,10 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -96,32 +95,14 @@ func (d *attributesDataSource) Read(ctx context.Context, req datasource.ReadRequ
 
 // fetchAllAttributes retrieves all custom attributes using the API
 func fetchAllAttributes(ctx context.Context, client *simplemdm.Client) ([]attributeData, error) {
-	url := fmt.Sprintf("https://%s/api/v1/custom_attributes", client.HostName)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := client.RequestResponse200(req)
-	if err != nil {
-		return nil, err
-	}
-
-	var response attributesAPIResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, err
-	}
-
-	return response.Data, nil
-}
-
-// attributesAPIResponse represents the API response for attributes list
-type attributesAPIResponse struct {
-	Data []attributeData `json:"data"`
+	return simplemdmext.ListAll(ctx, client, "custom_attributes", simplemdmext.ListOptions{}, func(attribute attributeData) string {
+		return attribute.ID
+	})
 }
 
 // attributeData represents a single attribute in the list response
 type attributeData struct {
+	ID         string                  `json:"id"`
 	Type       string                  `json:"type"`
 	Attributes attributeDataAttributes `json:"attributes"`
 }
@@ -136,7 +117,7 @@ func (d *attributesDataSource) Configure(_ context.Context, req datasource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -146,5 +127,5 @@ func (d *attributesDataSource) Configure(_ context.Context, req datasource.Confi
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
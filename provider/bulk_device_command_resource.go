@@ -0,0 +1,561 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &bulkDeviceCommandResource{}
+	_ resource.ResourceWithConfigure      = &bulkDeviceCommandResource{}
+	_ resource.ResourceWithValidateConfig = &bulkDeviceCommandResource{}
+	_ resource.ResourceWithModifyPlan     = &bulkDeviceCommandResource{}
+)
+
+const (
+	defaultBulkCommandConcurrency = 5
+	maxBulkCommandConcurrency     = 20
+
+	bulkCommandSucceeded = "succeeded"
+	bulkCommandFailed    = "failed"
+)
+
+type bulkDeviceCommandResource struct {
+	client *simplemdm.Client
+}
+
+type bulkDeviceCommandResourceModel struct {
+	ID                 types.String `tfsdk:"id"`
+	Command            types.String `tfsdk:"command"`
+	Parameters         types.Map    `tfsdk:"parameters"`
+	DeviceIDs          types.Set    `tfsdk:"device_ids"`
+	DeviceGroupIDs     types.Set    `tfsdk:"device_group_ids"`
+	AssignmentGroupIDs types.Set    `tfsdk:"assignment_group_ids"`
+	Search             types.String `tfsdk:"search"`
+	MaxConcurrency     types.Int64  `tfsdk:"max_concurrency"`
+	Triggers           types.Map    `tfsdk:"triggers"`
+	Results            types.List   `tfsdk:"results"`
+	SucceededCount     types.Int64  `tfsdk:"succeeded_count"`
+	FailedCount        types.Int64  `tfsdk:"failed_count"`
+}
+
+var bulkCommandResultAttrTypes = map[string]attr.Type{
+	"device_id":   types.StringType,
+	"status":      types.StringType,
+	"status_code": types.Int64Type,
+	"error":       types.StringType,
+}
+
+// bulkCommandResultModel maps an entry of the results attribute.
+type bulkCommandResultModel struct {
+	DeviceID   types.String `tfsdk:"device_id"`
+	Status     types.String `tfsdk:"status"`
+	StatusCode types.Int64  `tfsdk:"status_code"`
+	Error      types.String `tfsdk:"error"`
+}
+
+// bulkCommandTargets selects the devices a bulk command is sent to.
+type bulkCommandTargets struct {
+	deviceIDs          []string
+	deviceGroupIDs     []string
+	assignmentGroupIDs []string
+	search             string
+}
+
+func BulkDeviceCommandResource() resource.Resource {
+	return &bulkDeviceCommandResource{}
+}
+
+func (r *bulkDeviceCommandResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bulk_device_command"
+}
+
+func (r *bulkDeviceCommandResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	targetSet := func(description string) schema.SetAttribute {
+		return schema.SetAttribute{
+			Optional:    true,
+			ElementType: types.StringType,
+			PlanModifiers: []planmodifier.Set{
+				setplanmodifier.RequiresReplace(),
+			},
+			Description: description,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Sends a management command to every device selected by IDs, device groups, assignment groups or a search query.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Internal identifier for the bulk command.",
+			},
+			"command": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Description: "Command to send. Accepts the commands of simplemdm_device_command except the destructive ones (clear_passcode, delete_user, unenroll, wipe), which must target a single confirmed device.",
+			},
+			"parameters": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				Description: "Parameters sent to every device. They are validated as for simplemdm_device_command.",
+			},
+			"device_ids":           targetSet("Identifiers of devices to send the command to."),
+			"device_group_ids":     targetSet("Identifiers of device groups whose devices receive the command."),
+			"assignment_group_ids": targetSet("Identifiers of assignment groups whose devices, directly assigned or through device groups, receive the command."),
+			"search": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Description: "Device search query, as accepted by the SimpleMDM device list; every matching device receives the command.",
+			},
+			"max_concurrency": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(defaultBulkCommandConcurrency),
+				Description: "Maximum number of commands in flight at once, between 1 and 20. Defaults to 5.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, maxBulkCommandConcurrency),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that send the command again, without replacing the resource, whenever any of them changes. Targets are resolved again.",
+			},
+			"succeeded_count": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Description: "Number of devices that accepted the command.",
+			},
+			"failed_count": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Description: "Number of devices the command could not be sent to.",
+			},
+			"results": schema.ListNestedAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Outcome for each targeted device, ordered by device ID.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"device_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Device identifier.",
+						},
+						"status": schema.StringAttribute{
+							Computed:    true,
+							Description: "Either succeeded or failed.",
+						},
+						"status_code": schema.Int64Attribute{
+							Computed:    true,
+							Description: "HTTP status code returned by the SimpleMDM API, if a response was received.",
+						},
+						"error": schema.StringAttribute{
+							Computed:    true,
+							Description: "Error message for a failed device.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *bulkDeviceCommandResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		return
+	}
+
+	r.client = data.client
+}
+
+func (r *bulkDeviceCommandResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config bulkDeviceCommandResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.DeviceIDs.IsNull() && config.DeviceGroupIDs.IsNull() && config.AssignmentGroupIDs.IsNull() && config.Search.IsNull() {
+		resp.Diagnostics.AddError(
+			"Missing bulk command targets",
+			"Set at least one of device_ids, device_group_ids, assignment_group_ids or search.",
+		)
+	}
+
+	if config.Command.IsUnknown() || config.Command.IsNull() {
+		return
+	}
+
+	command := config.Command.ValueString()
+	spec, ok := bulkCommandSpec(command, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(validateCommandParameters(ctx, command, spec, config.Parameters)...)
+}
+
+func (r *bulkDeviceCommandResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state bulkDeviceCommandResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.Triggers.Equal(state.Triggers) {
+		return
+	}
+
+	plan.Results = types.ListUnknown(types.ObjectType{AttrTypes: bulkCommandResultAttrTypes})
+	plan.SucceededCount = types.Int64Unknown()
+	plan.FailedCount = types.Int64Unknown()
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *bulkDeviceCommandResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
+	if r.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "Unable to create bulk device command because the client was not configured")
+		return
+	}
+
+	var plan bulkDeviceCommandResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.dispatch(ctx, &plan, &resp.Diagnostics) {
+		return
+	}
+
+	plan.ID = types.StringValue(fmt.Sprintf("%s:%d", plan.Command.ValueString(), time.Now().UTC().Unix()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *bulkDeviceCommandResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state bulkDeviceCommandResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Commands cannot be read back from the API; retain state as-is.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *bulkDeviceCommandResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
+	if r.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "Unable to update bulk device command because the client was not configured")
+		return
+	}
+
+	var plan, state bulkDeviceCommandResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = state.ID
+
+	if plan.Triggers.Equal(state.Triggers) {
+		// Only max_concurrency changed; keep the last results.
+		plan.Results = state.Results
+		plan.SucceededCount = state.SucceededCount
+		plan.FailedCount = state.FailedCount
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	if !r.dispatch(ctx, &plan, &resp.Diagnostics) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *bulkDeviceCommandResource) Delete(_ context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	rejectReadOnly(r.client, &resp.Diagnostics, "delete")
+}
+
+// dispatch resolves the targets of plan, sends the command to every device
+// and records the results in plan. It reports whether plan should be saved:
+// the command reached at least one device, or no device matched.
+func (r *bulkDeviceCommandResource) dispatch(ctx context.Context, plan *bulkDeviceCommandResourceModel, diags *diag.Diagnostics) bool {
+	command := plan.Command.ValueString()
+	spec, ok := bulkCommandSpec(command, diags)
+	if !ok {
+		return false
+	}
+
+	// Parameters that were unknown during validation are checked once known.
+	diags.Append(validateCommandParameters(ctx, command, spec, plan.Parameters)...)
+	if diags.HasError() {
+		return false
+	}
+
+	params, ok := decodeCommandParameters(ctx, plan.Parameters, diags)
+	if !ok {
+		return false
+	}
+
+	var targets bulkCommandTargets
+	diags.Append(plan.DeviceIDs.ElementsAs(ctx, &targets.deviceIDs, false)...)
+	diags.Append(plan.DeviceGroupIDs.ElementsAs(ctx, &targets.deviceGroupIDs, false)...)
+	diags.Append(plan.AssignmentGroupIDs.ElementsAs(ctx, &targets.assignmentGroupIDs, false)...)
+	targets.search = plan.Search.ValueString()
+	if diags.HasError() {
+		return false
+	}
+
+	deviceIDs, err := resolveBulkTargets(ctx, r.client, targets)
+	if err != nil {
+		diags.AddError("Unable to resolve bulk command targets", err.Error())
+		return false
+	}
+
+	if len(deviceIDs) == 0 {
+		diags.AddWarning("No devices matched", fmt.Sprintf("The %q command was not sent because the targets selected no devices.", command))
+	}
+
+	concurrency := int(plan.MaxConcurrency.ValueInt64())
+	if concurrency < 1 {
+		concurrency = defaultBulkCommandConcurrency
+	}
+
+	results := dispatchBulkCommand(ctx, r.client, deviceIDs, spec, params, concurrency)
+
+	var failed []string
+	for _, result := range results {
+		if result.Status.ValueString() == bulkCommandFailed {
+			failed = append(failed, fmt.Sprintf("device %s: %s", result.DeviceID.ValueString(), result.Error.ValueString()))
+		}
+	}
+
+	list, d := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: bulkCommandResultAttrTypes}, results)
+	diags.Append(d...)
+	plan.Results = list
+	plan.SucceededCount = types.Int64Value(int64(len(results) - len(failed)))
+	plan.FailedCount = types.Int64Value(int64(len(failed)))
+
+	switch {
+	case len(failed) > 0 && len(failed) == len(results):
+		diags.AddError(
+			"Bulk device command failed",
+			fmt.Sprintf("The %q command could not be sent to any of the %d devices:\n%s", command, len(results), strings.Join(failed, "\n")),
+		)
+		return false
+	case len(failed) > 0:
+		diags.AddWarning(
+			"Bulk device command failed on some devices",
+			fmt.Sprintf("The %q command could not be sent to %d of %d devices; see the results attribute for details:\n%s", command, len(failed), len(results), strings.Join(failed, "\n")),
+		)
+	}
+
+	return !diags.HasError()
+}
+
+// bulkCommandSpec looks up command, which must not be destructive.
+func bulkCommandSpec(command string, diags *diag.Diagnostics) (deviceCommandSpec, bool) {
+	spec, ok := deviceCommandCatalog[command]
+	if !ok {
+		diags.AddAttributeError(
+			path.Root("command"),
+			"Unsupported device command",
+			fmt.Sprintf("Command %q is not currently supported by the provider", command),
+		)
+		return deviceCommandSpec{}, false
+	}
+
+	if spec.destructive {
+		diags.AddAttributeError(
+			path.Root("command"),
+			"Destructive device command not supported in bulk",
+			fmt.Sprintf("The %q command is destructive. Use simplemdm_device_command, which confirms the target device, to send it.", command),
+		)
+		return deviceCommandSpec{}, false
+	}
+
+	return spec, true
+}
+
+// resolveBulkTargets returns the IDs of the devices selected by targets,
+// ordered numerically. A device selected several times is included once.
+func resolveBulkTargets(ctx context.Context, client *simplemdm.Client, targets bulkCommandTargets) ([]string, error) {
+	selected := map[int]struct{}{}
+
+	for _, id := range targets.deviceIDs {
+		deviceID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid device ID %q", id)
+		}
+		selected[deviceID] = struct{}{}
+	}
+
+	groups := map[int]struct{}{}
+	for _, id := range targets.deviceGroupIDs {
+		groupID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid device group ID %q", id)
+		}
+		groups[groupID] = struct{}{}
+	}
+
+	for _, id := range targets.assignmentGroupIDs {
+		assignmentGroup, err := fetchAssignmentGroup(ctx, client, id)
+		if err != nil {
+			return nil, fmt.Errorf("reading assignment group %s: %w", id, err)
+		}
+
+		for _, device := range assignmentGroup.Data.Relationships.Devices.Data {
+			selected[device.ID] = struct{}{}
+		}
+		for _, group := range assignmentGroup.Data.Relationships.DeviceGroups.Data {
+			groups[group.ID] = struct{}{}
+		}
+	}
+
+	if len(groups) > 0 {
+		// The API has no group-scoped device listing, so group members are
+		// found through the device_group relationship of every device.
+		devices, err := simplemdmext.ListDevices(ctx, client, "", false, false)
+		if err != nil {
+			return nil, fmt.Errorf("listing devices: %w", err)
+		}
+
+		for _, device := range devices {
+			if _, ok := groups[device.Relationships.DeviceGroup.Data.ID]; ok {
+				selected[device.ID] = struct{}{}
+			}
+		}
+	}
+
+	if targets.search != "" {
+		devices, err := simplemdmext.ListDevices(ctx, client, targets.search, false, false)
+		if err != nil {
+			return nil, fmt.Errorf("searching devices: %w", err)
+		}
+
+		for _, device := range devices {
+			selected[device.ID] = struct{}{}
+		}
+	}
+
+	ids := make([]int, 0, len(selected))
+	for id := range selected {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	deviceIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		deviceIDs = append(deviceIDs, strconv.Itoa(id))
+	}
+
+	return deviceIDs, nil
+}
+
+// dispatchBulkCommand sends the command to each device with at most
+// concurrency requests in flight. Results are in the order of deviceIDs.
+func dispatchBulkCommand(ctx context.Context, client *simplemdm.Client, deviceIDs []string, spec deviceCommandSpec, params map[string]string, concurrency int) []bulkCommandResultModel {
+	results := make([]bulkCommandResultModel, len(deviceIDs))
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, deviceID := range deviceIDs {
+		slots <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			_, err := sendDeviceCommand(ctx, client, deviceID, spec, params)
+			results[i] = newBulkCommandResult(deviceID, spec.expectedStatus, err)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func newBulkCommandResult(deviceID string, expectedStatus int, err error) bulkCommandResultModel {
+	if err == nil {
+		return bulkCommandResultModel{
+			DeviceID:   types.StringValue(deviceID),
+			Status:     types.StringValue(bulkCommandSucceeded),
+			StatusCode: types.Int64Value(int64(expectedStatus)),
+			Error:      types.StringNull(),
+		}
+	}
+
+	result := bulkCommandResultModel{
+		DeviceID:   types.StringValue(deviceID),
+		Status:     types.StringValue(bulkCommandFailed),
+		StatusCode: types.Int64Null(),
+		Error:      types.StringValue(err.Error()),
+	}
+
+	if apiErr, ok := simplemdmext.AsAPIError(err); ok && apiErr.StatusCode != 0 {
+		result.StatusCode = types.Int64Value(int64(apiErr.StatusCode))
+	}
+
+	return result
+}
@@ -0,0 +1,252 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// bulkCommandTestServer serves devices 1 to 4, where devices 1 and 2 belong to
+// device group 10 and device 4 matches the search "lab", and assignment group
+// 7, which holds device 3 directly. Commands to device 4 fail.
+type bulkCommandTestServer struct {
+	client *simplemdm.Client
+
+	mu       sync.Mutex
+	commands []string
+
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func newBulkCommandTestServer(t *testing.T) *bulkCommandTestServer {
+	t.Helper()
+
+	s := &bulkCommandTestServer{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/devices" && r.URL.Query().Get("search") == "lab":
+			_, _ = w.Write([]byte(`{"data":[{"type":"device","id":4}],"has_more":false}`))
+		case r.URL.Path == "/api/v1/devices":
+			_, _ = w.Write([]byte(`{"data":[` +
+				`{"type":"device","id":1,"relationships":{"device_group":{"data":{"type":"device_group","id":10}}}},` +
+				`{"type":"device","id":2,"relationships":{"device_group":{"data":{"type":"device_group","id":10}}}},` +
+				`{"type":"device","id":3,"relationships":{"device_group":{"data":{"type":"device_group","id":11}}}},` +
+				`{"type":"device","id":4,"relationships":{"device_group":{"data":{"type":"device_group","id":11}}}}` +
+				`],"has_more":false}`))
+		case r.URL.Path == "/api/v1/assignment_groups/7":
+			_, _ = w.Write([]byte(`{"data":{"type":"assignment_group","id":7,"relationships":{"devices":{"data":[{"type":"device","id":3}]}}}}`))
+		default:
+			current := s.inFlight.Add(1)
+			defer s.inFlight.Add(-1)
+			for {
+				previous := s.maxInFlight.Load()
+				if current <= previous || s.maxInFlight.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			s.mu.Lock()
+			s.commands = append(s.commands, r.Method+" "+r.URL.Path)
+			s.mu.Unlock()
+
+			if strings.HasPrefix(r.URL.Path, "/api/v1/devices/4/") {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(`{"errors":[{"title":"device is not supervised"}]}`))
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	endpoint, err := simplemdmext.ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := simplemdmext.DefaultClientOptions()
+	options.Endpoint = endpoint
+	s.client, err = simplemdmext.NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return s
+}
+
+func stringSetValue(values ...string) tftypes.Value {
+	elements := make([]tftypes.Value, 0, len(values))
+	for _, value := range values {
+		elements = append(elements, tftypes.NewValue(tftypes.String, value))
+	}
+	return tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elements)
+}
+
+func TestResolveBulkTargets(t *testing.T) {
+	ctx := context.Background()
+	s := newBulkCommandTestServer(t)
+
+	tests := []struct {
+		name    string
+		targets bulkCommandTargets
+		want    []string
+	}{
+		{name: "device ids", targets: bulkCommandTargets{deviceIDs: []string{"12", "3", "3"}}, want: []string{"3", "12"}},
+		{name: "device group", targets: bulkCommandTargets{deviceGroupIDs: []string{"10"}}, want: []string{"1", "2"}},
+		{name: "assignment group", targets: bulkCommandTargets{assignmentGroupIDs: []string{"7"}}, want: []string{"3"}},
+		{name: "search", targets: bulkCommandTargets{search: "lab"}, want: []string{"4"}},
+		{name: "combined", targets: bulkCommandTargets{deviceIDs: []string{"2"}, deviceGroupIDs: []string{"10"}, search: "lab"}, want: []string{"1", "2", "4"}},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveBulkTargets(ctx, s.client, tt.targets)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := resolveBulkTargets(ctx, s.client, bulkCommandTargets{deviceIDs: []string{"abc"}}); err == nil {
+		t.Fatalf("expected an error for a non-numeric device ID")
+	}
+}
+
+func TestBulkDeviceCommandCreateRecordsPerDeviceResults(t *testing.T) {
+	ctx := context.Background()
+	s := newBulkCommandTestServer(t)
+
+	r := &bulkDeviceCommandResource{client: s.client}
+	schema, plan := resourceTestValue(ctx, t, r, map[string]tftypes.Value{
+		"command":              tftypes.NewValue(tftypes.String, "refresh"),
+		"device_group_ids":     stringSetValue("10"),
+		"assignment_group_ids": stringSetValue("7"),
+		"search":               tftypes.NewValue(tftypes.String, "lab"),
+		"max_concurrency":      tftypes.NewValue(tftypes.Number, 2),
+	})
+
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(plan.Type(), nil)}}
+	r.Create(ctx, resource.CreateRequest{Plan: tfsdk.Plan{Schema: schema, Raw: plan}}, resp)
+
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning for the failed device, got %v", resp.Diagnostics)
+	}
+
+	var state bulkDeviceCommandResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if state.SucceededCount.ValueInt64() != 3 || state.FailedCount.ValueInt64() != 1 {
+		t.Fatalf("unexpected counts: succeeded %v, failed %v", state.SucceededCount, state.FailedCount)
+	}
+
+	var results []bulkCommandResultModel
+	state.Results.ElementsAs(ctx, &results, false)
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	failed := results[3]
+	if failed.DeviceID.ValueString() != "4" || failed.Status.ValueString() != bulkCommandFailed || failed.StatusCode.ValueInt64() != http.StatusUnprocessableEntity {
+		t.Fatalf("unexpected result for device 4: %+v", failed)
+	}
+	if results[0].Status.ValueString() != bulkCommandSucceeded || !results[0].Error.IsNull() {
+		t.Fatalf("unexpected result for device 1: %+v", results[0])
+	}
+
+	if len(s.commands) != 4 {
+		t.Fatalf("expected 4 commands, got %v", s.commands)
+	}
+	if got := s.maxInFlight.Load(); got > 2 {
+		t.Fatalf("expected at most 2 commands in flight, got %d", got)
+	}
+}
+
+func TestBulkDeviceCommandCreateValidatesParameters(t *testing.T) {
+	ctx := context.Background()
+	s := newBulkCommandTestServer(t)
+
+	r := &bulkDeviceCommandResource{client: s.client}
+	schema, plan := resourceTestValue(ctx, t, r, map[string]tftypes.Value{
+		"command":    tftypes.NewValue(tftypes.String, "lock"),
+		"device_ids": stringSetValue("1"),
+		"parameters": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"pin": tftypes.NewValue(tftypes.String, "12ab"),
+		}),
+	})
+
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(plan.Type(), nil)}}
+	r.Create(ctx, resource.CreateRequest{Plan: tfsdk.Plan{Schema: schema, Raw: plan}}, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected the invalid pin to be rejected")
+	}
+	if len(s.commands) != 0 {
+		t.Fatalf("expected no commands, got %v", s.commands)
+	}
+}
+
+func TestBulkDeviceCommandValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	r := &bulkDeviceCommandResource{}
+
+	tests := []struct {
+		name      string
+		values    map[string]tftypes.Value
+		wantError string
+	}{
+		{
+			name:      "no targets",
+			values:    map[string]tftypes.Value{"command": tftypes.NewValue(tftypes.String, "refresh")},
+			wantError: "Missing bulk command targets",
+		},
+		{
+			name: "destructive",
+			values: map[string]tftypes.Value{
+				"command":    tftypes.NewValue(tftypes.String, "wipe"),
+				"device_ids": stringSetValue("1"),
+			},
+			wantError: "Destructive device command not supported in bulk",
+		},
+		{
+			name: "valid",
+			values: map[string]tftypes.Value{
+				"command": tftypes.NewValue(tftypes.String, "push_assigned_apps"),
+				"search":  tftypes.NewValue(tftypes.String, "lab"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		schema, config := resourceTestValue(ctx, t, r, tt.values)
+
+		resp := &resource.ValidateConfigResponse{}
+		r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schema, Raw: config}}, resp)
+
+		if tt.wantError == "" {
+			if resp.Diagnostics.HasError() {
+				t.Errorf("%s: unexpected diagnostics %v", tt.name, resp.Diagnostics)
+			}
+			continue
+		}
+		if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+			t.Errorf("%s: expected %q, got %v", tt.name, tt.wantError, resp.Diagnostics)
+		}
+	}
+}
@@ -69,6 +69,26 @@ func TestDataSourceDocumentationCoverage(t *testing.T) {
 	}
 }
 
+func TestActionDocumentationCoverage(t *testing.T) {
+	for _, definition := range ActionDefinitions() {
+		if definition.DocsPath != "" {
+			assertFileExists(t, definition.DocsPath)
+		}
+
+		for _, exampleDir := range definition.ExampleDirs {
+			assertExampleExists(t, exampleDir)
+		}
+
+		if len(definition.TestFiles) == 0 {
+			t.Fatalf("action %s is missing tests", definition.TypeName)
+		}
+
+		for _, testFile := range definition.TestFiles {
+			assertFileExists(t, testFile)
+		}
+	}
+}
+
 func assertFileExists(t *testing.T, path string) {
 	t.Helper()
 
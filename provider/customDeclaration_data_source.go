@@ -3,11 +3,10 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -150,7 +149,7 @@ func (d *customDeclarationDataSource) Configure(_ context.Context, req datasourc
 		return
 	}
 
-	d.client = req.ProviderData.(*simplemdm.Client)
+	d.client = req.ProviderData.(*providerData).client
 }
 
 func (d *customDeclarationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -161,16 +160,16 @@ func (d *customDeclarationDataSource) Read(ctx context.Context, req datasource.R
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/custom_declarations/%s", d.client.HostName, state.ID.ValueString())
+	url := simplemdmext.APIURL(d.client, "custom_declarations/%s", state.ID.ValueString())
 	httpReq, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating SimpleMDM custom declaration request", err.Error())
 		return
 	}
 
-	responseBody, err := d.client.RequestResponse200(httpReq)
+	responseBody, err := simplemdmext.Do(d.client, httpReq, http.StatusOK)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.Diagnostics.AddError("Custom declaration not found", err.Error())
 			return
 		}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -73,10 +74,14 @@ func (r *customDeclarationDeviceAssignmentResource) Configure(_ context.Context,
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 func (r *customDeclarationDeviceAssignmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	var plan customDeclarationDeviceAssignmentModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -84,14 +89,14 @@ func (r *customDeclarationDeviceAssignmentResource) Create(ctx context.Context,
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/custom_declarations/%s/devices/%s", r.client.HostName, plan.CustomDeclarationID.ValueString(), plan.DeviceID.ValueString())
+	url := simplemdmext.APIURL(r.client, "custom_declarations/%s/devices/%s", plan.CustomDeclarationID.ValueString(), plan.DeviceID.ValueString())
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating SimpleMDM custom declaration assignment request", err.Error())
 		return
 	}
 
-	if _, err := r.client.RequestResponse204or409(httpReq); err != nil {
+	if _, err := simplemdmext.Do(r.client, httpReq, http.StatusNoContent, http.StatusConflict); err != nil {
 		resp.Diagnostics.AddError("Error assigning custom declaration to device", err.Error())
 		return
 	}
@@ -103,6 +108,10 @@ func (r *customDeclarationDeviceAssignmentResource) Create(ctx context.Context,
 }
 
 func (r *customDeclarationDeviceAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	resp.Diagnostics.AddError(
 		"Cannot update custom declaration assignments",
 		"Updates are not supported. Remove and recreate the assignment to target a different device or declaration.",
@@ -117,16 +126,16 @@ func (r *customDeclarationDeviceAssignmentResource) Read(ctx context.Context, re
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/devices/%s", r.client.HostName, state.DeviceID.ValueString())
+	url := simplemdmext.APIURL(r.client, "devices/%s", state.DeviceID.ValueString())
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating SimpleMDM device request", err.Error())
 		return
 	}
 
-	body, err := r.client.RequestResponse200(httpReq)
+	body, err := simplemdmext.Do(r.client, httpReq, http.StatusOK)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -153,6 +162,10 @@ func (r *customDeclarationDeviceAssignmentResource) Read(ctx context.Context, re
 }
 
 func (r *customDeclarationDeviceAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state customDeclarationDeviceAssignmentModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -160,15 +173,15 @@ func (r *customDeclarationDeviceAssignmentResource) Delete(ctx context.Context,
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/custom_declarations/%s/devices/%s", r.client.HostName, state.CustomDeclarationID.ValueString(), state.DeviceID.ValueString())
+	url := simplemdmext.APIURL(r.client, "custom_declarations/%s/devices/%s", state.CustomDeclarationID.ValueString(), state.DeviceID.ValueString())
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating SimpleMDM custom declaration assignment request", err.Error())
 		return
 	}
 
-	if _, err := r.client.RequestResponse204or409(httpReq); err != nil {
-		if strings.Contains(err.Error(), "404") {
+	if _, err := simplemdmext.Do(r.client, httpReq, http.StatusNoContent, http.StatusConflict); err != nil {
+		if isNotFoundError(err) {
 			return
 		}
 
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
@@ -25,13 +26,13 @@ func testAccCheckCustomDeclarationDeviceAssignmentDestroy(s *terraform.State) er
 		deviceID := rs.Primary.Attributes["device_id"]
 
 		// Check if the device still has the custom declaration assigned
-		url := fmt.Sprintf("https://%s/api/v1/devices/%s", client.HostName, deviceID)
+		url := simplemdmext.APIURL(client, "devices/%s", deviceID)
 		httpReq, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
 			return err
 		}
 
-		body, err := client.RequestResponse200(httpReq)
+		body, err := simplemdmext.Do(client, httpReq, http.StatusOK)
 		if err != nil {
 			// If device doesn't exist, the assignment is definitely destroyed
 			if isNotFoundError(err) {
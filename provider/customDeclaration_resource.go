@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -236,10 +237,14 @@ func (r *customDeclarationResource) Configure(_ context.Context, req resource.Co
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 func (r *customDeclarationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	var plan customDeclarationResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -263,7 +268,7 @@ func (r *customDeclarationResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/custom_declarations", r.client.HostName)
+	url := simplemdmext.APIURL(r.client, "custom_declarations")
 	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(bodyBytes))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating SimpleMDM custom declaration request", err.Error())
@@ -271,7 +276,7 @@ func (r *customDeclarationResource) Create(ctx context.Context, req resource.Cre
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	responseBody, err := r.client.RequestResponse201(httpReq)
+	responseBody, err := simplemdmext.Do(r.client, httpReq, http.StatusCreated)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating SimpleMDM custom declaration", err.Error())
 		return
@@ -310,16 +315,16 @@ func (r *customDeclarationResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/custom_declarations/%s", r.client.HostName, state.ID.ValueString())
+	url := simplemdmext.APIURL(r.client, "custom_declarations/%s", state.ID.ValueString())
 	httpReq, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating SimpleMDM custom declaration request", err.Error())
 		return
 	}
 
-	responseBody, err := r.client.RequestResponse200(httpReq)
+	responseBody, err := simplemdmext.Do(r.client, httpReq, http.StatusOK)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -354,6 +359,10 @@ func (r *customDeclarationResource) Read(ctx context.Context, req resource.ReadR
 }
 
 func (r *customDeclarationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	var plan customDeclarationResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -377,7 +386,7 @@ func (r *customDeclarationResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/custom_declarations/%s", r.client.HostName, plan.ID.ValueString())
+	url := simplemdmext.APIURL(r.client, "custom_declarations/%s", plan.ID.ValueString())
 	httpReq, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(bodyBytes))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating SimpleMDM custom declaration request", err.Error())
@@ -385,7 +394,7 @@ func (r *customDeclarationResource) Update(ctx context.Context, req resource.Upd
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	responseBody, err := r.client.RequestResponse200(httpReq)
+	responseBody, err := simplemdmext.Do(r.client, httpReq, http.StatusOK)
 	if err != nil {
 		resp.Diagnostics.AddError("Error updating SimpleMDM custom declaration", err.Error())
 		return
@@ -417,6 +426,10 @@ func (r *customDeclarationResource) Update(ctx context.Context, req resource.Upd
 }
 
 func (r *customDeclarationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state customDeclarationResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -424,16 +437,16 @@ func (r *customDeclarationResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/custom_declarations/%s", r.client.HostName, state.ID.ValueString())
+	url := simplemdmext.APIURL(r.client, "custom_declarations/%s", state.ID.ValueString())
 	httpReq, err := http.NewRequest(http.MethodDelete, url, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating SimpleMDM custom declaration request", err.Error())
 		return
 	}
 
-	_, err = r.client.RequestResponse204(httpReq)
+	_, err = simplemdmext.Do(r.client, httpReq, http.StatusNoContent)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			return
 		}
 
@@ -655,13 +668,13 @@ func (m *customDeclarationResourceModel) refreshFromResponse(ctx context.Context
 }
 
 func downloadCustomDeclarationPayload(ctx context.Context, client *simplemdm.Client, declarationID string) (json.RawMessage, error) {
-	url := fmt.Sprintf("https://%s/api/v1/custom_declarations/%s/download", client.HostName, declarationID)
+	url := simplemdmext.APIURL(client, "custom_declarations/%s/download", declarationID)
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := client.RequestResponse200(httpReq)
+	body, err := simplemdmext.Do(client, httpReq, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -2,24 +2,24 @@ package provider
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"testing"
 
 	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func testAccCheckCustomDeclarationDestroy(s *terraform.State) error {
 	return testAccCheckResourceDestroyed("simplemdm_customdeclaration", func(client *simplemdm.Client, id string) error {
-		url := fmt.Sprintf("https://%s/api/v1/custom_declarations/%s", client.HostName, id)
+		url := simplemdmext.APIURL(client, "custom_declarations/%s", id)
 		httpReq, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
 			return err
 		}
 
-		responseBody, err := client.RequestResponse200(httpReq)
+		responseBody, err := simplemdmext.Do(client, httpReq, http.StatusOK)
 		if err != nil {
 			return err
 		}
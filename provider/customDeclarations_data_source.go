@@ -2,12 +2,11 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"sort"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -236,7 +235,7 @@ func (d *customDeclarationsDataSource) Configure(_ context.Context, req datasour
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -246,56 +245,14 @@ func (d *customDeclarationsDataSource) Configure(_ context.Context, req datasour
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 // fetchAllCustomDeclarations retrieves all custom declarations with pagination support
 func fetchAllCustomDeclarations(ctx context.Context, client *simplemdm.Client) ([]customDeclarationDataList, error) {
-	var allDeclarations []customDeclarationDataList
-	startingAfter := ""
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/custom_declarations?limit=%d", client.HostName, limit)
-		if startingAfter != "" {
-			url += fmt.Sprintf("&starting_after=%s", startingAfter)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		body, err := client.RequestResponse200(req)
-		if err != nil {
-			return nil, err
-		}
-
-		var response customDeclarationsAPIResponse
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, err
-		}
-
-		allDeclarations = append(allDeclarations, response.Data...)
-
-		if !response.HasMore {
-			break
-		}
-
-		if len(response.Data) > 0 {
-			startingAfter = response.Data[len(response.Data)-1].ID
-		} else {
-			break
-		}
-	}
-
-	return allDeclarations, nil
-}
-
-// customDeclarationsAPIResponse represents the paginated API response
-type customDeclarationsAPIResponse struct {
-	Data    []customDeclarationDataList `json:"data"`
-	HasMore bool                        `json:"has_more"`
+	return simplemdmext.ListAll(ctx, client, "custom_declarations", simplemdmext.ListOptions{}, func(declaration customDeclarationDataList) string {
+		return declaration.ID
+	})
 }
 
 // customDeclarationDataList represents a single declaration in the list response
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -108,7 +107,7 @@ func (d *customProfileDataSource) Read(ctx context.Context, req datasource.ReadR
 
 	profile, err := d.client.CustomProfileGet(state.ID.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.Diagnostics.AddError(
 				"Error Reading SimpleMDM custom profile",
 				"Custom profile with ID "+state.ID.ValueString()+" was not found.",
@@ -134,7 +133,7 @@ func (d *customProfileDataSource) Read(ctx context.Context, req datasource.ReadR
 
 	sha, body, err := d.client.CustomProfileSHA(state.ID.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.Diagnostics.AddError(
 				"Error Reading SimpleMDM custom profile",
 				"Custom profile payload for ID "+state.ID.ValueString()+" was not found.",
@@ -167,7 +166,7 @@ func (d *customProfileDataSource) Configure(_ context.Context, req datasource.Co
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -177,5 +176,5 @@ func (d *customProfileDataSource) Configure(_ context.Context, req datasource.Co
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"strconv"
-	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -53,7 +52,7 @@ func (r *customProfileResource) Configure(_ context.Context, req resource.Config
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -134,6 +133,10 @@ func (r *customProfileResource) ImportState(ctx context.Context, req resource.Im
 
 // Create a new resource
 func (r *customProfileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan customProfileResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -187,7 +190,7 @@ func (r *customProfileResource) Read(ctx context.Context, req resource.ReadReque
 
 	profile, err := r.client.CustomProfileGet(state.ID.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -204,7 +207,7 @@ func (r *customProfileResource) Read(ctx context.Context, req resource.ReadReque
 
 	sha, body, err := r.client.CustomProfileSHA(state.ID.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -228,6 +231,10 @@ func (r *customProfileResource) Read(ctx context.Context, req resource.ReadReque
 }
 
 func (r *customProfileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan customProfileResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -248,7 +255,7 @@ func (r *customProfileResource) Update(ctx context.Context, req resource.UpdateR
 
 	profile, err := r.client.CustomProfileGet(plan.ID.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -264,7 +271,7 @@ func (r *customProfileResource) Update(ctx context.Context, req resource.UpdateR
 
 	sha, body, err := r.client.CustomProfileSHA(plan.ID.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -287,6 +294,10 @@ func (r *customProfileResource) Update(ctx context.Context, req resource.UpdateR
 }
 
 func (r *customProfileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state customProfileResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -296,7 +307,7 @@ func (r *customProfileResource) Delete(ctx context.Context, req resource.DeleteR
 
 	// Delete existing custom profile
 	err := r.client.CustomProfileDelete(state.ID.ValueString())
-	if err != nil {
+	if err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting SimpleMDM custom profile",
 			"Could not delete custom profile, unexpected error: "+err.Error(),
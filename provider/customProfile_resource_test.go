@@ -35,7 +35,9 @@ func TestAccCustomProfileResource(t *testing.T) {
 
 		  }
 `,
-				ExpectNonEmptyPlan: true,
+				// The real API may still report the previous profile settings;
+				// the fake API is consistent, so the plan must be empty there.
+				ExpectNonEmptyPlan: testAccEventuallyConsistent(),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					// Verify attributes
 					resource.TestCheckResourceAttr("simplemdm_customprofile.test", "name", "testprofile"),
@@ -73,7 +75,9 @@ resource "simplemdm_customprofile" "test" {
 
 				  }
 `,
-				ExpectNonEmptyPlan: true,
+				// The real API may still report the previous profile settings;
+				// the fake API is consistent, so the plan must be empty there.
+				ExpectNonEmptyPlan: testAccEventuallyConsistent(),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					// Verify attributes
 					resource.TestCheckResourceAttr("simplemdm_customprofile.test", "name", "testprofile2"),
@@ -2,12 +2,11 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strconv"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -142,7 +141,7 @@ func (d *customProfilesDataSource) Configure(_ context.Context, req datasource.C
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -152,56 +151,14 @@ func (d *customProfilesDataSource) Configure(_ context.Context, req datasource.C
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 // fetchAllCustomProfiles retrieves all custom profiles with pagination support
 func fetchAllCustomProfiles(ctx context.Context, client *simplemdm.Client) ([]customProfileData, error) {
-	var allProfiles []customProfileData
-	startingAfter := 0
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/custom_profiles?limit=%d", client.HostName, limit)
-		if startingAfter > 0 {
-			url += fmt.Sprintf("&starting_after=%d", startingAfter)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		body, err := client.RequestResponse200(req)
-		if err != nil {
-			return nil, err
-		}
-
-		var response customProfilesAPIResponse
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, err
-		}
-
-		allProfiles = append(allProfiles, response.Data...)
-
-		if !response.HasMore {
-			break
-		}
-
-		if len(response.Data) > 0 {
-			startingAfter = response.Data[len(response.Data)-1].ID
-		} else {
-			break
-		}
-	}
-
-	return allProfiles, nil
-}
-
-// customProfilesAPIResponse represents the paginated API response for custom profiles list
-type customProfilesAPIResponse struct {
-	Data    []customProfileData `json:"data"`
-	HasMore bool                `json:"has_more"`
+	return simplemdmext.ListAll(ctx, client, "custom_configuration_profiles", simplemdmext.ListOptions{}, simplemdmext.IntCursor(func(profile customProfileData) int {
+		return profile.ID
+	}))
 }
 
 // customProfileData represents a single custom profile in the list response
@@ -99,7 +99,7 @@ func (d *deviceGroupDataSource) Configure(_ context.Context, req datasource.Conf
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -109,5 +109,5 @@ func (d *deviceGroupDataSource) Configure(_ context.Context, req datasource.Conf
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -51,7 +52,7 @@ func (r *deviceGroupResource) Configure(_ context.Context, req resource.Configur
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -112,6 +113,10 @@ func (r *deviceGroupResource) ImportState(ctx context.Context, req resource.Impo
 
 // Create a new resource
 func (r *deviceGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan deviceGroupResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -285,6 +290,10 @@ func (r *deviceGroupResource) Read(ctx context.Context, req resource.ReadRequest
 }
 
 func (r *deviceGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan, state deviceGroupResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -325,6 +334,10 @@ func (r *deviceGroupResource) Update(ctx context.Context, req resource.UpdateReq
 }
 
 func (r *deviceGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state deviceGroupResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -332,7 +345,7 @@ func (r *deviceGroupResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	if err := r.client.DeviceGroupDelete(state.ID.ValueString()); err != nil {
+	if err := r.client.DeviceGroupDelete(state.ID.ValueString()); err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError(
 			"Error deleting SimpleMDM device group",
 			"Could not delete SimpleMDM device group "+state.ID.ValueString()+": "+err.Error(),
@@ -472,7 +485,7 @@ func extractStringSet(set types.Set) []string {
 }
 
 func updateDeviceGroupName(ctx context.Context, client *simplemdm.Client, groupID, name string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("https://%s/api/v1/device_groups/%s", client.HostName, groupID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, simplemdmext.APIURL(client, "device_groups/%s", groupID), nil)
 	if err != nil {
 		return err
 	}
@@ -481,17 +494,17 @@ func updateDeviceGroupName(ctx context.Context, client *simplemdm.Client, groupI
 	query.Add("name", name)
 	req.URL.RawQuery = query.Encode()
 
-	_, err = client.RequestResponse200(req)
+	_, err = simplemdmext.Do(client, req, http.StatusOK)
 	return err
 }
 
 func cloneDeviceGroup(ctx context.Context, client *simplemdm.Client, sourceID string) (*simplemdm.SimplemdmDefaultStruct, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("https://%s/api/v1/device_groups/%s/clone", client.HostName, sourceID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, simplemdmext.APIURL(client, "device_groups/%s/clone", sourceID), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := client.RequestResponse200(req)
+	body, err := simplemdmext.Do(client, req, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
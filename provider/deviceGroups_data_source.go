@@ -2,12 +2,11 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strconv"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -103,7 +102,7 @@ func (d *deviceGroupsDataSource) Configure(_ context.Context, req datasource.Con
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -113,56 +112,14 @@ func (d *deviceGroupsDataSource) Configure(_ context.Context, req datasource.Con
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 // fetchAllDeviceGroups retrieves all device groups with pagination support
 func fetchAllDeviceGroups(ctx context.Context, client *simplemdm.Client) ([]deviceGroupData, error) {
-	var allGroups []deviceGroupData
-	startingAfter := 0
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/device_groups?limit=%d", client.HostName, limit)
-		if startingAfter > 0 {
-			url += fmt.Sprintf("&starting_after=%d", startingAfter)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		body, err := client.RequestResponse200(req)
-		if err != nil {
-			return nil, err
-		}
-
-		var response deviceGroupsAPIResponse
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, err
-		}
-
-		allGroups = append(allGroups, response.Data...)
-
-		if !response.HasMore {
-			break
-		}
-
-		if len(response.Data) > 0 {
-			startingAfter = response.Data[len(response.Data)-1].ID
-		} else {
-			break
-		}
-	}
-
-	return allGroups, nil
-}
-
-// deviceGroupsAPIResponse represents the paginated API response
-type deviceGroupsAPIResponse struct {
-	Data    []deviceGroupData `json:"data"`
-	HasMore bool              `json:"has_more"`
+	return simplemdmext.ListAll(ctx, client, "device_groups", simplemdmext.ListOptions{}, simplemdmext.IntCursor(func(group deviceGroupData) int {
+		return group.ID
+	}))
 }
 
 // deviceGroupData represents a single device group in the list response
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ action.Action                   = &deviceCommandAction{}
+	_ action.ActionWithConfigure      = &deviceCommandAction{}
+	_ action.ActionWithValidateConfig = &deviceCommandAction{}
+	_ action.ActionWithModifyPlan     = &deviceCommandAction{}
+)
+
+// deviceCommandAction sends a command from deviceCommandCatalog without
+// recording anything in state. It accepts the same settings as the
+// simplemdm_device_command resource and guards them the same way.
+type deviceCommandAction struct {
+	client   *simplemdm.Client
+	settings providerSettings
+}
+
+type deviceCommandActionModel struct {
+	DeviceID            types.String `tfsdk:"device_id"`
+	Command             types.String `tfsdk:"command"`
+	Parameters          types.Map    `tfsdk:"parameters"`
+	ConfirmDeviceSerial types.String `tfsdk:"confirm_device_serial"`
+	ConfirmDeviceName   types.String `tfsdk:"confirm_device_name"`
+}
+
+func (m deviceCommandActionModel) target() deviceCommandTarget {
+	return deviceCommandTarget{
+		DeviceID:            m.DeviceID,
+		Command:             m.Command,
+		Parameters:          m.Parameters,
+		ConfirmDeviceSerial: m.ConfirmDeviceSerial,
+		ConfirmDeviceName:   m.ConfirmDeviceName,
+	}
+}
+
+func DeviceCommandAction() action.Action {
+	return &deviceCommandAction{}
+}
+
+func (a *deviceCommandAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_command"
+}
+
+func (a *deviceCommandAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Sends a management command to a SimpleMDM device when the action is invoked, without keeping a record in state. Requires Terraform 1.14 or later.",
+		Attributes: map[string]schema.Attribute{
+			"device_id": schema.StringAttribute{
+				Required:    true,
+				Description: deviceIDDescription,
+			},
+			"command": schema.StringAttribute{
+				Required:    true,
+				Description: deviceCommandDescription,
+			},
+			"confirm_device_serial": schema.StringAttribute{
+				Optional:    true,
+				Description: confirmDeviceSerialDescription,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"confirm_device_name": schema.StringAttribute{
+				Optional:    true,
+				Description: confirmDeviceNameDescription,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"parameters": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: deviceCommandParametersDescription,
+			},
+		},
+	}
+}
+
+func (a *deviceCommandAction) Configure(_ context.Context, req action.ConfigureRequest, _ *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		return
+	}
+
+	a.client = data.client
+	a.settings = data.settings
+}
+
+func (a *deviceCommandAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config deviceCommandActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateDeviceCommandConfig(ctx, config.target())...)
+}
+
+func (a *deviceCommandAction) ModifyPlan(ctx context.Context, req action.ModifyPlanRequest, resp *action.ModifyPlanResponse) {
+	var config deviceCommandActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(planDeviceCommand(ctx, a.client, a.settings, config.target())...)
+}
+
+func (a *deviceCommandAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	if rejectReadOnlyAction(a.client, &resp.Diagnostics) {
+		return
+	}
+
+	if a.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "Unable to send device command because the client was not configured")
+		return
+	}
+
+	var config deviceCommandActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	command := config.Command.ValueString()
+	deviceID := config.DeviceID.ValueString()
+
+	spec, ok := resolveDeviceCommandSpec(command, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	if !authorizeDeviceCommand(ctx, a.client, a.settings, config.target(), spec, &resp.Diagnostics) {
+		return
+	}
+
+	params, ok := decodeCommandParameters(ctx, config.Parameters, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	if _, err := sendDeviceCommand(ctx, a.client, deviceID, spec, params); err != nil {
+		resp.Diagnostics.AddError("Error executing device command", err.Error())
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("SimpleMDM accepted the %q command for device %s.", command, deviceID),
+	})
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// actionTestConfig builds a configuration for the schema of a from the given
+// attribute values; every other attribute is null.
+func actionTestConfig(ctx context.Context, t *testing.T, a action.Action, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+
+	schemaResp := &action.SchemaResponse{}
+	a.Schema(ctx, action.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("action schema is not an object")
+	}
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+			continue
+		}
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	return tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)}
+}
+
+func invokeDeviceCommandAction(ctx context.Context, t *testing.T, a *deviceCommandAction, values map[string]tftypes.Value) (*action.InvokeResponse, []string) {
+	t.Helper()
+
+	var progress []string
+	resp := &action.InvokeResponse{
+		SendProgress: func(event action.InvokeProgressEvent) {
+			progress = append(progress, event.Message)
+		},
+	}
+	a.Invoke(ctx, action.InvokeRequest{Config: actionTestConfig(ctx, t, a, values)}, resp)
+
+	return resp, progress
+}
+
+func TestDeviceCommandActionInvoke(t *testing.T) {
+	ctx := context.Background()
+
+	var commands []string
+	client := newDeviceCommandTestServer(t, "C02ABC", "Laptop", &commands)
+	a := &deviceCommandAction{client: client}
+
+	resp, progress := invokeDeviceCommandAction(ctx, t, a, map[string]tftypes.Value{
+		"device_id": tftypes.NewValue(tftypes.String, "1"),
+		"command":   tftypes.NewValue(tftypes.String, "restart"),
+		"parameters": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			"notify_user": tftypes.NewValue(tftypes.String, "true"),
+		}),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(commands) != 1 || commands[0] != "POST /api/v1/devices/1/restart" {
+		t.Fatalf("unexpected commands: %v", commands)
+	}
+	if len(progress) != 1 {
+		t.Fatalf("expected a progress message, got %v", progress)
+	}
+}
+
+func TestDeviceCommandActionGuardsDestructiveCommands(t *testing.T) {
+	ctx := context.Background()
+
+	var commands []string
+	client := newDeviceCommandTestServer(t, "C02ABC", "Laptop", &commands)
+	a := &deviceCommandAction{client: client}
+
+	values := map[string]tftypes.Value{
+		"device_id": tftypes.NewValue(tftypes.String, "1"),
+		"command":   tftypes.NewValue(tftypes.String, "unenroll"),
+	}
+
+	validateResp := &action.ValidateConfigResponse{}
+	a.ValidateConfig(ctx, action.ValidateConfigRequest{Config: actionTestConfig(ctx, t, a, values)}, validateResp)
+	if !validateResp.Diagnostics.HasError() || validateResp.Diagnostics.Errors()[0].Summary() != "Missing device confirmation" {
+		t.Fatalf("expected a missing confirmation error, got %v", validateResp.Diagnostics)
+	}
+
+	values["confirm_device_name"] = tftypes.NewValue(tftypes.String, "Laptop")
+
+	planResp := &action.ModifyPlanResponse{}
+	a.ModifyPlan(ctx, action.ModifyPlanRequest{Config: actionTestConfig(ctx, t, a, values)}, planResp)
+	if !planResp.Diagnostics.HasError() || planResp.Diagnostics.Errors()[0].Summary() != "Destructive device command not allowed" {
+		t.Fatalf("expected the allowlist to reject the command, got %v", planResp.Diagnostics)
+	}
+
+	resp, _ := invokeDeviceCommandAction(ctx, t, a, values)
+	if !resp.Diagnostics.HasError() || len(commands) != 0 {
+		t.Fatalf("expected the command to be rejected, got %v (%v)", resp.Diagnostics, commands)
+	}
+
+	a.settings = providerSettings{allowedDestructiveCommands: map[string]struct{}{"unenroll": {}}}
+
+	resp, _ = invokeDeviceCommandAction(ctx, t, a, values)
+	if resp.Diagnostics.HasError() || len(commands) != 1 {
+		t.Fatalf("expected the confirmed command to be sent, got %v (%v)", resp.Diagnostics, commands)
+	}
+}
+
+func TestProviderServesDeviceCommandAction(t *testing.T) {
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected diagnostic: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	if _, ok := resp.ActionSchemas["simplemdm_device_command"]; !ok {
+		t.Fatalf("simplemdm_device_command action is not served, got %v", resp.ActionSchemas)
+	}
+	if _, ok := resp.ResourceSchemas["simplemdm_device_command"]; !ok {
+		t.Fatalf("simplemdm_device_command resource must remain available")
+	}
+}
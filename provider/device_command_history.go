@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultCommandHistoryLimit is the number of executions kept in history when
+// history_limit is not set.
+const defaultCommandHistoryLimit = 10
+
+var deviceCommandExecutionAttrTypes = map[string]attr.Type{
+	"executed_at": types.StringType,
+	"status_code": types.Int64Type,
+	"response":    types.StringType,
+}
+
+// deviceCommandExecutionModel maps an entry of the history attribute.
+type deviceCommandExecutionModel struct {
+	ExecutedAt types.String `tfsdk:"executed_at"`
+	StatusCode types.Int64  `tfsdk:"status_code"`
+	Response   types.String `tfsdk:"response"`
+}
+
+func newDeviceCommandExecution(executedAt time.Time, statusCode int, body []byte) deviceCommandExecutionModel {
+	execution := deviceCommandExecutionModel{
+		ExecutedAt: types.StringValue(executedAt.UTC().Format(time.RFC3339)),
+		StatusCode: types.Int64Value(int64(statusCode)),
+		Response:   types.StringNull(),
+	}
+
+	if len(body) > 0 {
+		execution.Response = types.StringValue(string(body))
+	}
+
+	return execution
+}
+
+// appendCommandExecution adds execution to the end of history and drops the
+// oldest entries beyond limit. A null or unknown history starts empty.
+func appendCommandExecution(ctx context.Context, history types.List, execution deviceCommandExecutionModel, limit int64) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	executions := make([]deviceCommandExecutionModel, 0)
+	if !history.IsNull() && !history.IsUnknown() {
+		diags.Append(history.ElementsAs(ctx, &executions, false)...)
+		if diags.HasError() {
+			return history, diags
+		}
+	}
+
+	executions = append(executions, execution)
+
+	return trimCommandHistory(ctx, executions, limit, diags)
+}
+
+// limitCommandHistory drops the oldest entries of history beyond limit.
+func limitCommandHistory(ctx context.Context, history types.List, limit int64) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if history.IsNull() || history.IsUnknown() {
+		return history, diags
+	}
+
+	executions := make([]deviceCommandExecutionModel, 0)
+	diags.Append(history.ElementsAs(ctx, &executions, false)...)
+	if diags.HasError() {
+		return history, diags
+	}
+
+	return trimCommandHistory(ctx, executions, limit, diags)
+}
+
+func trimCommandHistory(ctx context.Context, executions []deviceCommandExecutionModel, limit int64, diags diag.Diagnostics) (types.List, diag.Diagnostics) {
+	if limit < 1 {
+		limit = defaultCommandHistoryLimit
+	}
+	if excess := int64(len(executions)) - limit; excess > 0 {
+		executions = executions[excess:]
+	}
+
+	list, d := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: deviceCommandExecutionAttrTypes}, executions)
+	diags.Append(d...)
+
+	return list, diags
+}
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// withAttributes returns a copy of the object value with the given attributes
+// replaced.
+func withAttributes(t *testing.T, value tftypes.Value, replacements map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	current := map[string]tftypes.Value{}
+	if err := value.As(&current); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// As shares the map of value, so build a new one.
+	attributes := maps.Clone(current)
+	for name, replacement := range replacements {
+		attributes[name] = replacement
+	}
+
+	return tftypes.NewValue(value.Type(), attributes)
+}
+
+func triggersValue(values map[string]string) tftypes.Value {
+	elements := make(map[string]tftypes.Value, len(values))
+	for key, value := range values {
+		elements[key] = tftypes.NewValue(tftypes.String, value)
+	}
+	return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, elements)
+}
+
+func updateDeviceCommand(ctx context.Context, t *testing.T, r *deviceCommandResource, state tfsdk.State, replacements map[string]tftypes.Value) deviceCommandResourceModel {
+	t.Helper()
+
+	plan := tfsdk.Plan{Schema: state.Schema, Raw: withAttributes(t, state.Raw, replacements)}
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: state.Schema, Raw: plan.Raw}}
+	r.Update(ctx, resource.UpdateRequest{Plan: plan, State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var model deviceCommandResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	return model
+}
+
+func TestDeviceCommandUpdateReissuesOnTriggerChange(t *testing.T) {
+	ctx := context.Background()
+
+	var commands []string
+	client := newDeviceCommandTestServer(t, "C02ABC", "Laptop", &commands)
+	r := &deviceCommandResource{client: client}
+
+	created := createDeviceCommand(ctx, t, client, providerSettings{}, map[string]tftypes.Value{
+		"device_id":     tftypes.NewValue(tftypes.String, "1"),
+		"command":       tftypes.NewValue(tftypes.String, "refresh"),
+		"triggers":      triggersValue(map[string]string{"build": "1"}),
+		"history_limit": tftypes.NewValue(tftypes.Number, 2),
+	})
+	if created.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", created.Diagnostics)
+	}
+
+	state := created.State
+	for _, build := range []string{"2", "3"} {
+		model := updateDeviceCommand(ctx, t, r, state, map[string]tftypes.Value{
+			"triggers": triggersValue(map[string]string{"build": build}),
+		})
+		if err := state.Set(ctx, &model); err.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", err)
+		}
+	}
+
+	if len(commands) != 3 {
+		t.Fatalf("expected the command to be sent 3 times, got %v", commands)
+	}
+
+	var model deviceCommandResourceModel
+	state.Get(ctx, &model)
+	if len(model.History.Elements()) != 2 {
+		t.Fatalf("expected history to be limited to 2 entries, got %v", model.History)
+	}
+
+	model = updateDeviceCommand(ctx, t, r, state, map[string]tftypes.Value{
+		"history_limit": tftypes.NewValue(tftypes.Number, 1),
+	})
+	if len(commands) != 3 {
+		t.Fatalf("changing history_limit must not send the command: %v", commands)
+	}
+	if len(model.History.Elements()) != 1 || model.StatusCode.ValueInt64() != 202 {
+		t.Fatalf("unexpected state after changing history_limit: %+v", model)
+	}
+}
+
+func TestMarkReissuedOutcomeUnknown(t *testing.T) {
+	state := deviceCommandResourceModel{
+		DeviceID:     types.StringValue("1"),
+		Command:      types.StringValue("refresh"),
+		Parameters:   types.MapNull(types.StringType),
+		Triggers:     types.MapValueMust(types.StringType, map[string]attr.Value{"build": types.StringValue("1")}),
+		HistoryLimit: types.Int64Value(10),
+		StatusCode:   types.Int64Value(202),
+	}
+
+	plan := state
+	if markReissuedOutcomeUnknown(&plan, state) || !plan.History.IsNull() || plan.StatusCode.IsUnknown() {
+		t.Fatalf("unchanged plan must not send the command: %+v", plan)
+	}
+
+	plan = state
+	plan.HistoryLimit = types.Int64Value(5)
+	if markReissuedOutcomeUnknown(&plan, state) || !plan.History.IsUnknown() || plan.StatusCode.IsUnknown() {
+		t.Fatalf("history_limit change must only recompute history: %+v", plan)
+	}
+
+	plan = state
+	plan.Triggers = types.MapValueMust(types.StringType, map[string]attr.Value{"build": types.StringValue("2")})
+	if !markReissuedOutcomeUnknown(&plan, state) || !plan.History.IsUnknown() || !plan.StatusCode.IsUnknown() {
+		t.Fatalf("trigger change must send the command: %+v", plan)
+	}
+}
+
+func TestAppendCommandExecutionDropsOldestEntries(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	history := types.ListNull(types.ObjectType{AttrTypes: deviceCommandExecutionAttrTypes})
+	for i := range 4 {
+		var diags diag.Diagnostics
+		history, diags = appendCommandExecution(ctx, history, newDeviceCommandExecution(start.Add(time.Duration(i)*time.Minute), 202, nil), 3)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+	}
+
+	var executions []deviceCommandExecutionModel
+	history.ElementsAs(ctx, &executions, false)
+	if len(executions) != 3 {
+		t.Fatalf("expected 3 executions, got %d", len(executions))
+	}
+	if executions[0].ExecutedAt.ValueString() != "2024-05-01T10:01:00Z" || executions[2].ExecutedAt.ValueString() != "2024-05-01T10:03:00Z" {
+		t.Fatalf("unexpected executions: %+v", executions)
+	}
+	if !executions[0].Response.IsNull() {
+		t.Fatalf("expected an empty response to be null, got %v", executions[0].Response)
+	}
+}
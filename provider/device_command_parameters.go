@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	// Embed the time zone database so set_time_zone validation does not
+	// depend on the zoneinfo files of the machine running Terraform.
+	_ "time/tzdata"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// commandParameterKind is the type a device command parameter value must have.
+// Values are always passed as strings and parsed for validation only.
+type commandParameterKind string
+
+const (
+	parameterString  commandParameterKind = "string"
+	parameterInteger commandParameterKind = "integer"
+	parameterBoolean commandParameterKind = "boolean"
+)
+
+// commandParameter declares a parameter accepted by a device command.
+type commandParameter struct {
+	kind     commandParameterKind
+	required bool
+	// allowed restricts the value to a fixed set, if not empty.
+	allowed []string
+	// validate applies additional checks to the value, if set.
+	validate func(string) error
+}
+
+var sixDigitPIN = regexp.MustCompile(`^[0-9]{6}$`)
+
+func validatePIN(value string) error {
+	if !sixDigitPIN.MatchString(value) {
+		return fmt.Errorf("must be a 6 digit number")
+	}
+	return nil
+}
+
+func validateTimeZone(value string) error {
+	if _, err := time.LoadLocation(value); err != nil || value == "" || value == "Local" {
+		return fmt.Errorf("must be an IANA time zone name such as Europe/Berlin")
+	}
+	return nil
+}
+
+var (
+	restartParameters = map[string]commandParameter{
+		"rebuild_kernel_cache": {kind: parameterBoolean},
+		"notify_user":          {kind: parameterBoolean},
+	}
+	lockParameters = map[string]commandParameter{
+		"message":      {kind: parameterString},
+		"phone_number": {kind: parameterString},
+		"pin":          {kind: parameterString, validate: validatePIN},
+	}
+	setAdminPasswordParameters = map[string]commandParameter{
+		"new_password": {kind: parameterString, required: true},
+	}
+	wipeParameters = map[string]commandParameter{
+		"pin":                      {kind: parameterString, validate: validatePIN},
+		"preserve_data_plan":       {kind: parameterBoolean},
+		"disallow_proximity_setup": {kind: parameterBoolean},
+	}
+	updateOSParameters = map[string]commandParameter{
+		"os_update_mode": {kind: parameterString, required: true, allowed: []string{"smart_update", "download_only", "notify_only", "install_asap", "force_update"}},
+		"version_type":   {kind: parameterString, allowed: []string{"latest_minor_version", "latest_major_version"}},
+	}
+	setTimeZoneParameters = map[string]commandParameter{
+		"time_zone": {kind: parameterString, required: true, validate: validateTimeZone},
+	}
+	deleteUserParameters = map[string]commandParameter{
+		"user_id": {kind: parameterInteger, required: true},
+	}
+)
+
+// validateCommandParameters checks parameters against the declaration of
+// command. Unknown values are skipped so the check can run again once they
+// are known. Every diagnostic points at the offending map key.
+func validateCommandParameters(ctx context.Context, command string, spec deviceCommandSpec, parameters types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if parameters.IsUnknown() {
+		return diags
+	}
+
+	values := map[string]types.String{}
+	if !parameters.IsNull() {
+		diags.Append(parameters.ElementsAs(ctx, &values, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+		keyPath := path.Root("parameters").AtMapKey(key)
+
+		declaration, ok := spec.parameters[key]
+		if !ok {
+			diags.AddAttributeError(
+				keyPath,
+				"Unsupported device command parameter",
+				fmt.Sprintf("Command %q does not accept parameter %q. %s", command, key, describeCommandParameters(spec)),
+			)
+			continue
+		}
+
+		if value.IsUnknown() || value.IsNull() || value.ValueString() == "" {
+			continue
+		}
+
+		if err := declaration.check(value.ValueString()); err != nil {
+			diags.AddAttributeError(
+				keyPath,
+				"Invalid device command parameter",
+				fmt.Sprintf("Parameter %q of command %q %s.", key, command, err),
+			)
+		}
+	}
+
+	required := make([]string, 0)
+	for key, declaration := range spec.parameters {
+		if !declaration.required {
+			continue
+		}
+		if value, ok := values[key]; ok && (value.IsUnknown() || value.ValueString() != "") {
+			continue
+		}
+		required = append(required, key)
+	}
+	sort.Strings(required)
+
+	for _, key := range required {
+		diags.AddAttributeError(
+			path.Root("parameters").AtMapKey(key),
+			"Missing device command parameter",
+			fmt.Sprintf("Command %q requires parameter %q.", command, key),
+		)
+	}
+
+	return diags
+}
+
+func (p commandParameter) check(value string) error {
+	switch p.kind {
+	case parameterInteger:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("must be an integer, got %q", value)
+		}
+	case parameterBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("must be true or false, got %q", value)
+		}
+	}
+
+	if len(p.allowed) > 0 && !slices.Contains(p.allowed, value) {
+		return fmt.Errorf("must be one of %s, got %q", strings.Join(p.allowed, ", "), value)
+	}
+
+	if p.validate != nil {
+		if err := p.validate(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// describeCommandParameters lists the parameters a command accepts for use in
+// diagnostics.
+func describeCommandParameters(spec deviceCommandSpec) string {
+	if len(spec.parameters) == 0 {
+		return "The command takes no parameters."
+	}
+
+	keys := make([]string, 0, len(spec.parameters))
+	for key := range spec.parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return "Supported parameters: " + strings.Join(keys, ", ") + "."
+}
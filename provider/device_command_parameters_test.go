@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func commandParametersValue(values map[string]string) types.Map {
+	elements := make(map[string]attr.Value, len(values))
+	for key, value := range values {
+		elements[key] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+func TestValidateCommandParameters(t *testing.T) {
+	tests := []struct {
+		command   string
+		params    map[string]string
+		wantKey   string
+		wantError string
+	}{
+		{command: "refresh", params: map[string]string{}},
+		{command: "lock", params: map[string]string{"message": "Lost", "pin": "123456"}},
+		{command: "lock", params: map[string]string{"pin": "12ab"}, wantKey: "pin", wantError: "6 digit"},
+		{command: "lock", params: map[string]string{"pincode": "123456"}, wantKey: "pincode", wantError: "does not accept"},
+		{command: "refresh", params: map[string]string{"force": "true"}, wantKey: "force", wantError: "takes no parameters"},
+		{command: "set_time_zone", params: map[string]string{"time_zone": "Europe/Berlin"}},
+		{command: "set_time_zone", params: map[string]string{"time_zone": "Mars/Olympus"}, wantKey: "time_zone", wantError: "IANA"},
+		{command: "set_time_zone", params: map[string]string{}, wantKey: "time_zone", wantError: "requires parameter"},
+		{command: "update_os", params: map[string]string{"os_update_mode": "install_asap", "version_type": "latest_minor_version"}},
+		{command: "update_os", params: map[string]string{"os_update_mode": "whenever"}, wantKey: "os_update_mode", wantError: "must be one of"},
+		{command: "restart", params: map[string]string{"notify_user": "yes"}, wantKey: "notify_user", wantError: "true or false"},
+		{command: "delete_user", params: map[string]string{"user_id": "abc"}, wantKey: "user_id", wantError: "integer"},
+	}
+
+	for _, tt := range tests {
+		diags := validateCommandParameters(context.Background(), tt.command, deviceCommandCatalog[tt.command], commandParametersValue(tt.params))
+
+		if tt.wantError == "" {
+			if diags.HasError() {
+				t.Errorf("%s %v: unexpected diagnostics %v", tt.command, tt.params, diags)
+			}
+			continue
+		}
+
+		if diags.ErrorsCount() != 1 {
+			t.Errorf("%s %v: expected one error, got %v", tt.command, tt.params, diags)
+			continue
+		}
+
+		withPath, ok := diags.Errors()[0].(interface{ Path() path.Path })
+		if !ok || !withPath.Path().Equal(path.Root("parameters").AtMapKey(tt.wantKey)) {
+			t.Errorf("%s %v: error does not point at parameter %q", tt.command, tt.params, tt.wantKey)
+		}
+		if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, tt.wantError) || !strings.Contains(detail, tt.wantKey) {
+			t.Errorf("%s %v: unexpected detail %q", tt.command, tt.params, detail)
+		}
+	}
+}
+
+func TestValidateCommandParametersSkipsUnknownValues(t *testing.T) {
+	params := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"time_zone": types.StringUnknown(),
+	})
+
+	if diags := validateCommandParameters(context.Background(), "set_time_zone", deviceCommandCatalog["set_time_zone"], params); diags.HasError() {
+		t.Fatalf("unknown values must be validated later, got %v", diags)
+	}
+}
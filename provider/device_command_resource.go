@@ -3,28 +3,43 @@ package provider
 import (
 	"context"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
 var (
-	_ resource.Resource                = &deviceCommandResource{}
-	_ resource.ResourceWithConfigure   = &deviceCommandResource{}
-	_ resource.ResourceWithImportState = &deviceCommandResource{}
+	_ resource.Resource                   = &deviceCommandResource{}
+	_ resource.ResourceWithConfigure      = &deviceCommandResource{}
+	_ resource.ResourceWithImportState    = &deviceCommandResource{}
+	_ resource.ResourceWithValidateConfig = &deviceCommandResource{}
+	_ resource.ResourceWithModifyPlan     = &deviceCommandResource{}
 )
 
 type deviceCommandResource struct {
-	client *simplemdm.Client
+	client   *simplemdm.Client
+	settings providerSettings
 }
 
 type deviceCommandResourceModel struct {
@@ -34,16 +49,45 @@ type deviceCommandResourceModel struct {
 	Parameters types.Map    `tfsdk:"parameters"`
 	StatusCode types.Int64  `tfsdk:"status_code"`
 	Response   types.String `tfsdk:"response"`
+
+	ConfirmDeviceSerial types.String `tfsdk:"confirm_device_serial"`
+	ConfirmDeviceName   types.String `tfsdk:"confirm_device_name"`
+
+	WaitFor            types.Object `tfsdk:"wait_for"`
+	CompletedAt        types.String `tfsdk:"completed_at"`
+	ObservedLastSeenAt types.String `tfsdk:"observed_last_seen_at"`
+	ObservedOSVersion  types.String `tfsdk:"observed_os_version"`
+
+	Triggers     types.Map   `tfsdk:"triggers"`
+	HistoryLimit types.Int64 `tfsdk:"history_limit"`
+	History      types.List  `tfsdk:"history"`
+}
+
+func (m deviceCommandResourceModel) target() deviceCommandTarget {
+	return deviceCommandTarget{
+		DeviceID:            m.DeviceID,
+		Command:             m.Command,
+		Parameters:          m.Parameters,
+		ConfirmDeviceSerial: m.ConfirmDeviceSerial,
+		ConfirmDeviceName:   m.ConfirmDeviceName,
+	}
 }
 
 type deviceCommandSpec struct {
 	method         string
 	pathTemplate   string
 	expectedStatus int
+	// destructive marks commands that erase data or remove the device from
+	// management. They must be allowed by the provider and confirmed against
+	// the live device.
+	destructive bool
+	// parameters declares the accepted parameters. Commands without
+	// declarations take none.
+	parameters map[string]commandParameter
 }
 
 const (
-	deviceCommandEndpointFormat   = "https://%s/api/v1/devices/%s/%s"
+	deviceCommandEndpointFormat   = "devices/%s/%s"
 	contentTypeFormURLEncoded     = "application/x-www-form-urlencoded"
 	deviceCommandIDFormatTemplate = "%s:%s:%d"
 )
@@ -51,29 +95,39 @@ const (
 var deviceCommandCatalog = map[string]deviceCommandSpec{
 	"push_assigned_apps":            {method: http.MethodPost, pathTemplate: "push_apps", expectedStatus: http.StatusAccepted},
 	"refresh":                       {method: http.MethodPost, pathTemplate: "refresh", expectedStatus: http.StatusAccepted},
-	"restart":                       {method: http.MethodPost, pathTemplate: "restart", expectedStatus: http.StatusAccepted},
+	"restart":                       {method: http.MethodPost, pathTemplate: "restart", expectedStatus: http.StatusAccepted, parameters: restartParameters},
 	"shutdown":                      {method: http.MethodPost, pathTemplate: "shutdown", expectedStatus: http.StatusAccepted},
-	"lock":                          {method: http.MethodPost, pathTemplate: "lock", expectedStatus: http.StatusAccepted},
-	"clear_passcode":                {method: http.MethodPost, pathTemplate: "clear_passcode", expectedStatus: http.StatusAccepted},
+	"lock":                          {method: http.MethodPost, pathTemplate: "lock", expectedStatus: http.StatusAccepted, parameters: lockParameters},
+	"clear_passcode":                {method: http.MethodPost, pathTemplate: "clear_passcode", expectedStatus: http.StatusAccepted, destructive: true},
 	"clear_firmware_password":       {method: http.MethodPost, pathTemplate: "clear_firmware_password", expectedStatus: http.StatusAccepted},
 	"rotate_firmware_password":      {method: http.MethodPost, pathTemplate: "rotate_firmware_password", expectedStatus: http.StatusAccepted},
 	"clear_recovery_lock_password":  {method: http.MethodPost, pathTemplate: "clear_recovery_lock_password", expectedStatus: http.StatusAccepted},
 	"clear_restrictions_password":   {method: http.MethodPost, pathTemplate: "clear_restrictions_password", expectedStatus: http.StatusAccepted},
 	"rotate_recovery_lock_password": {method: http.MethodPost, pathTemplate: "rotate_recovery_lock_password", expectedStatus: http.StatusAccepted},
 	"rotate_filevault_recovery_key": {method: http.MethodPost, pathTemplate: "rotate_filevault_key", expectedStatus: http.StatusAccepted},
-	"set_admin_password":            {method: http.MethodPost, pathTemplate: "set_admin_password", expectedStatus: http.StatusAccepted},
+	"set_admin_password":            {method: http.MethodPost, pathTemplate: "set_admin_password", expectedStatus: http.StatusAccepted, parameters: setAdminPasswordParameters},
 	"rotate_admin_password":         {method: http.MethodPost, pathTemplate: "rotate_admin_password", expectedStatus: http.StatusAccepted},
-	"wipe":                          {method: http.MethodPost, pathTemplate: "wipe", expectedStatus: http.StatusAccepted},
-	"update_os":                     {method: http.MethodPost, pathTemplate: "update_os", expectedStatus: http.StatusAccepted},
+	"wipe":                          {method: http.MethodPost, pathTemplate: "wipe", expectedStatus: http.StatusAccepted, destructive: true, parameters: wipeParameters},
+	"update_os":                     {method: http.MethodPost, pathTemplate: "update_os", expectedStatus: http.StatusAccepted, parameters: updateOSParameters},
 	"enable_remote_desktop":         {method: http.MethodPost, pathTemplate: "remote_desktop", expectedStatus: http.StatusAccepted},
 	"disable_remote_desktop":        {method: http.MethodDelete, pathTemplate: "remote_desktop", expectedStatus: http.StatusAccepted},
 	"enable_bluetooth":              {method: http.MethodPost, pathTemplate: "bluetooth", expectedStatus: http.StatusAccepted},
 	"disable_bluetooth":             {method: http.MethodDelete, pathTemplate: "bluetooth", expectedStatus: http.StatusAccepted},
-	"set_time_zone":                 {method: http.MethodPost, pathTemplate: "set_time_zone", expectedStatus: http.StatusNoContent},
-	"unenroll":                      {method: http.MethodPost, pathTemplate: "unenroll", expectedStatus: http.StatusAccepted},
-	"delete_user":                   {method: http.MethodDelete, pathTemplate: "users/{user_id}", expectedStatus: http.StatusAccepted},
+	"set_time_zone":                 {method: http.MethodPost, pathTemplate: "set_time_zone", expectedStatus: http.StatusNoContent, parameters: setTimeZoneParameters},
+	"unenroll":                      {method: http.MethodPost, pathTemplate: "unenroll", expectedStatus: http.StatusAccepted, destructive: true},
+	"delete_user":                   {method: http.MethodDelete, pathTemplate: "users/{user_id}", expectedStatus: http.StatusAccepted, destructive: true, parameters: deleteUserParameters},
 }
 
+// Descriptions of the attributes shared by the simplemdm_device_command
+// resource and action.
+const (
+	deviceIDDescription                = "Identifier of the target device."
+	deviceCommandDescription           = "Command to execute. Destructive commands (clear_passcode, delete_user, unenroll, wipe) must be listed in the provider's allowed_destructive_commands. Supported values include push_assigned_apps, refresh, restart, shutdown, lock, clear_passcode, clear_firmware_password, rotate_firmware_password, clear_recovery_lock_password, clear_restrictions_password, rotate_recovery_lock_password, rotate_filevault_recovery_key, set_admin_password, rotate_admin_password, wipe, update_os, enable_remote_desktop, disable_remote_desktop, enable_bluetooth, disable_bluetooth, set_time_zone, unenroll, delete_user."
+	confirmDeviceSerialDescription     = "Serial number the target device must report before a destructive command (clear_passcode, delete_user, unenroll, wipe) is sent. Either this or confirm_device_name is required for destructive commands."
+	confirmDeviceNameDescription       = "Name the target device must have before a destructive command is sent. Either this or confirm_device_serial is required for destructive commands."
+	deviceCommandParametersDescription = "Parameters to pass to the API call. Each command accepts its own set and unknown keys are rejected: restart takes rebuild_kernel_cache and notify_user (true/false); lock takes message, phone_number and a 6 digit pin; set_admin_password requires new_password; wipe takes a 6 digit pin, preserve_data_plan and disallow_proximity_setup (true/false); update_os requires os_update_mode (smart_update, download_only, notify_only, install_asap, force_update) and takes version_type (latest_minor_version, latest_major_version); set_time_zone requires an IANA time_zone; delete_user requires the numeric user_id. Other commands take no parameters."
+)
+
 func DeviceCommandResource() resource.Resource {
 	return &deviceCommandResource{}
 }
@@ -94,26 +148,116 @@ func (r *deviceCommandResource) Schema(_ context.Context, _ resource.SchemaReque
 				Description: "Internal identifier for the executed command.",
 			},
 			"device_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Identifier of the target device.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Description: deviceIDDescription,
 			},
 			"command": schema.StringAttribute{
-				Required:    true,
-				Description: "Command to execute. Supported values include push_assigned_apps, refresh, restart, shutdown, lock, clear_passcode, clear_firmware_password, rotate_firmware_password, clear_recovery_lock_password, clear_restrictions_password, rotate_recovery_lock_password, rotate_filevault_recovery_key, set_admin_password, rotate_admin_password, wipe, update_os, enable_remote_desktop, disable_remote_desktop, enable_bluetooth, disable_bluetooth, set_time_zone, unenroll, delete_user.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Description: deviceCommandDescription,
+			},
+			"confirm_device_serial": schema.StringAttribute{
+				Optional:    true,
+				Description: confirmDeviceSerialDescription,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"confirm_device_name": schema.StringAttribute{
+				Optional:    true,
+				Description: confirmDeviceNameDescription,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"parameters": schema.MapAttribute{
 				Optional:    true,
 				ElementType: types.StringType,
-				Description: "Optional parameters to pass to the API call.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				Description: deviceCommandParametersDescription,
 			},
 			"status_code": schema.Int64Attribute{
-				Computed:    true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 				Description: "HTTP status code returned by the SimpleMDM API.",
 			},
 			"response": schema.StringAttribute{
-				Computed:    true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Description: "Raw response payload, if any, returned by the API.",
 			},
+			"completed_at": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Time at which wait_for observed the expected outcome. Null without wait_for.",
+			},
+			"observed_last_seen_at": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Last check-in time the device reported when wait_for finished. Null without wait_for.",
+			},
+			"observed_os_version": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "OS version the device reported when wait_for finished. Null without wait_for.",
+			},
+			"triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that send the command again, without replacing the resource, whenever any of them changes.",
+			},
+			"history_limit": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(defaultCommandHistoryLimit),
+				Description: "Number of executions kept in history, defaults to 10.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"history": schema.ListNestedAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Most recent executions of the command, oldest first.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"executed_at": schema.StringAttribute{
+							Computed:    true,
+							Description: "Time at which the command was sent.",
+						},
+						"status_code": schema.Int64Attribute{
+							Computed:    true,
+							Description: "HTTP status code returned by the SimpleMDM API.",
+						},
+						"response": schema.StringAttribute{
+							Computed:    true,
+							Description: "Raw response payload, if any, returned by the API.",
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"wait_for": deviceCommandWaitBlock(),
 		},
 	}
 }
@@ -123,57 +267,115 @@ func (r *deviceCommandResource) Configure(_ context.Context, req resource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.settings = data.settings
 }
 
 func (r *deviceCommandResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	if r.client == nil {
 		resp.Diagnostics.AddError("Provider not configured", "Unable to create device command because the client was not configured")
 		return
 	}
 
-	plan, ok := r.readCreatePlan(ctx, req, resp)
-	if !ok {
+	var plan deviceCommandResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	commandKey := plan.Command.ValueString()
-	spec, ok := resolveDeviceCommandSpec(commandKey, resp)
-	if !ok {
+	plan.History = types.ListNull(types.ObjectType{AttrTypes: deviceCommandExecutionAttrTypes})
+	if !r.issueCommand(ctx, &plan, &resp.Diagnostics) {
 		return
 	}
 
-	params, ok := decodeCommandParameters(ctx, plan.Parameters, resp)
+	plan.ID = types.StringValue(fmt.Sprintf(deviceCommandIDFormatTemplate, plan.DeviceID.ValueString(), plan.Command.ValueString(), time.Now().UTC().Unix()))
+
+	// The state is saved even when waiting failed, because the command was
+	// sent; Terraform marks the resource tainted and a later apply sends it
+	// again.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// issueCommand sends the planned command and records the outcome in plan,
+// appending it to plan.History. It reports whether the command was sent; plan
+// must then be saved even if diags holds errors from waiting for it.
+func (r *deviceCommandResource) issueCommand(ctx context.Context, plan *deviceCommandResourceModel, diags *diag.Diagnostics) bool {
+	commandKey := plan.Command.ValueString()
+	spec, ok := resolveDeviceCommandSpec(commandKey, diags)
 	if !ok {
-		return
+		return false
 	}
 
-	pathFragment, consumedKeys, err := expandCommandPath(spec.pathTemplate, params)
-	if err != nil {
-		resp.Diagnostics.AddError("Invalid command parameters", err.Error())
-		return
+	if !authorizeDeviceCommand(ctx, r.client, r.settings, plan.target(), spec, diags) {
+		return false
 	}
 
-	removeConsumedParameters(params, consumedKeys)
+	params, ok := decodeCommandParameters(ctx, plan.Parameters, diags)
+	if !ok {
+		return false
+	}
 
-	reqObj, err := r.buildCommandRequest(ctx, spec.method, plan.DeviceID.ValueString(), pathFragment, params)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating request", err.Error())
-		return
+	var wait *deviceCommandWait
+	var baseline deviceSnapshot
+	var err error
+	if !plan.WaitFor.IsNull() {
+		var waitModel deviceCommandWaitModel
+		diags.Append(plan.WaitFor.As(ctx, &waitModel, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return false
+		}
+
+		resolved := newDeviceCommandWait(waitModel, commandKey)
+		wait = &resolved
+
+		baseline, err = snapshotDevice(ctx, r.client, plan.DeviceID.ValueString())
+		if err != nil {
+			diags.AddError("Unable to read device before sending command", err.Error())
+			return false
+		}
 	}
 
-	body, err := r.executeCommand(reqObj, spec.expectedStatus)
+	body, err := sendDeviceCommand(ctx, r.client, plan.DeviceID.ValueString(), spec, params)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing device command", err.Error())
-		return
+		diags.AddError("Error executing device command", err.Error())
+		return false
+	}
+
+	acceptedAt := time.Now()
+	execution := newDeviceCommandExecution(acceptedAt, spec.expectedStatus, body)
+	plan.StatusCode = execution.StatusCode
+	plan.Response = execution.Response
+	plan.CompletedAt = types.StringNull()
+	plan.ObservedLastSeenAt = types.StringNull()
+	plan.ObservedOSVersion = types.StringNull()
+
+	history, d := appendCommandExecution(ctx, plan.History, execution, plan.HistoryLimit.ValueInt64())
+	diags.Append(d...)
+	plan.History = history
+
+	if wait != nil {
+		observed, err := waitForDevice(ctx, r.client, plan.DeviceID.ValueString(), *wait, baseline, acceptedAt)
+		plan.ObservedLastSeenAt = stringValueOrNull(observed.LastSeenAt)
+		plan.ObservedOSVersion = stringValueOrNull(observed.OSVersion)
+
+		if err != nil {
+			diags.AddError("Device command did not complete", err.Error())
+			return true
+		}
+
+		plan.CompletedAt = types.StringValue(time.Now().UTC().Format(time.RFC3339))
 	}
 
-	r.updateCreateState(ctx, plan, commandKey, spec.expectedStatus, body, resp)
+	return true
 }
 
 func (r *deviceCommandResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -184,15 +386,129 @@ func (r *deviceCommandResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
-	// Commands are fire-and-forget; retain state as-is.
+	// Commands cannot be read back from the API; retain state as-is. Outcomes
+	// are recorded when the command is sent.
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *deviceCommandResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError("Device commands cannot be updated", "Remove the resource and recreate it to issue another command.")
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
+	if r.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "Unable to update device command because the client was not configured")
+		return
+	}
+
+	var plan, state deviceCommandResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = state.ID
+	plan.History = state.History
+
+	if plan.Triggers.Equal(state.Triggers) {
+		// Only settings such as confirm_device_serial or history_limit
+		// changed; keep the outcome of the last execution.
+		plan.StatusCode = state.StatusCode
+		plan.Response = state.Response
+		plan.CompletedAt = state.CompletedAt
+		plan.ObservedLastSeenAt = state.ObservedLastSeenAt
+		plan.ObservedOSVersion = state.ObservedOSVersion
+
+		history, diags := limitCommandHistory(ctx, state.History, plan.HistoryLimit.ValueInt64())
+		resp.Diagnostics.Append(diags...)
+		plan.History = history
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	if !r.issueCommand(ctx, &plan, &resp.Diagnostics) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *deviceCommandResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+}
+
+func (r *deviceCommandResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config deviceCommandResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateDeviceCommandConfig(ctx, config.target())...)
+}
+
+func (r *deviceCommandResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan deviceCommandResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A creation sends the command, and so does an update that changes
+	// triggers.
+	if !req.State.Raw.IsNull() {
+		var state deviceCommandResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if !markReissuedOutcomeUnknown(&plan, state) {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(planDeviceCommand(ctx, r.client, r.settings, plan.target())...)
 }
 
-func (r *deviceCommandResource) Delete(ctx context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+// markReissuedOutcomeUnknown updates the planned outcome attributes, which
+// otherwise keep their state values, for an update. It reports whether the
+// update sends the command again.
+func markReissuedOutcomeUnknown(plan *deviceCommandResourceModel, state deviceCommandResourceModel) bool {
+	// A replacement is planned as an update here; it creates the resource,
+	// which sends the command too.
+	replace := !plan.DeviceID.Equal(state.DeviceID) || !plan.Command.Equal(state.Command) || !plan.Parameters.Equal(state.Parameters)
+	reissue := replace || !plan.Triggers.Equal(state.Triggers)
+
+	if reissue || !plan.HistoryLimit.Equal(state.HistoryLimit) {
+		plan.History = types.ListUnknown(types.ObjectType{AttrTypes: deviceCommandExecutionAttrTypes})
+	}
+
+	if !reissue {
+		return false
+	}
+
+	plan.StatusCode = types.Int64Unknown()
+	plan.Response = types.StringUnknown()
+	plan.CompletedAt = types.StringUnknown()
+	plan.ObservedLastSeenAt = types.StringUnknown()
+	plan.ObservedOSVersion = types.StringUnknown()
+
+	return true
 }
 
 func (r *deviceCommandResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -219,21 +535,10 @@ func expandCommandPath(template string, params map[string]string) (string, []str
 	return expanded, consumed, nil
 }
 
-func (r *deviceCommandResource) readCreatePlan(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) (*deviceCommandResourceModel, bool) {
-	var plan deviceCommandResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return nil, false
-	}
-
-	return &plan, true
-}
-
-func resolveDeviceCommandSpec(commandKey string, resp *resource.CreateResponse) (deviceCommandSpec, bool) {
+func resolveDeviceCommandSpec(commandKey string, diags *diag.Diagnostics) (deviceCommandSpec, bool) {
 	spec, ok := deviceCommandCatalog[commandKey]
 	if !ok {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Unsupported device command",
 			fmt.Sprintf("Command %q is not currently supported by the provider", commandKey),
 		)
@@ -243,15 +548,14 @@ func resolveDeviceCommandSpec(commandKey string, resp *resource.CreateResponse)
 	return spec, true
 }
 
-func decodeCommandParameters(ctx context.Context, parameters types.Map, resp *resource.CreateResponse) (map[string]string, bool) {
+func decodeCommandParameters(ctx context.Context, parameters types.Map, diags *diag.Diagnostics) (map[string]string, bool) {
 	if parameters.IsNull() || parameters.IsUnknown() {
 		return map[string]string{}, true
 	}
 
 	result := make(map[string]string)
-	diags := parameters.ElementsAs(ctx, &result, false)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+	diags.Append(parameters.ElementsAs(ctx, &result, false)...)
+	if diags.HasError() {
 		return nil, false
 	}
 
@@ -270,8 +574,29 @@ func removeConsumedParameters(params map[string]string, keys []string) {
 	}
 }
 
-func (r *deviceCommandResource) buildCommandRequest(ctx context.Context, method, deviceID, pathFragment string, params map[string]string) (*http.Request, error) {
-	endpoint := fmt.Sprintf(deviceCommandEndpointFormat, r.client.HostName, deviceID, pathFragment)
+// sendDeviceCommand sends the command described by spec to a device and
+// returns the response body. params is not modified, so it can be shared
+// between devices.
+func sendDeviceCommand(ctx context.Context, client *simplemdm.Client, deviceID string, spec deviceCommandSpec, params map[string]string) ([]byte, error) {
+	params = maps.Clone(params)
+
+	pathFragment, consumedKeys, err := expandCommandPath(spec.pathTemplate, params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command parameters: %w", err)
+	}
+
+	removeConsumedParameters(params, consumedKeys)
+
+	req, err := buildCommandRequest(ctx, client, spec.method, deviceID, pathFragment, params)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	return simplemdmext.Do(client, req, spec.expectedStatus)
+}
+
+func buildCommandRequest(ctx context.Context, client *simplemdm.Client, method, deviceID, pathFragment string, params map[string]string) (*http.Request, error) {
+	endpoint := simplemdmext.APIURL(client, deviceCommandEndpointFormat, deviceID, pathFragment)
 
 	bodyReader, hasBody := prepareCommandBody(method, params)
 
@@ -287,7 +612,7 @@ func (r *deviceCommandResource) buildCommandRequest(ctx context.Context, method,
 	return req, nil
 }
 
-func prepareCommandBody(method string, params map[string]string) (*strings.Reader, bool) {
+func prepareCommandBody(method string, params map[string]string) (io.Reader, bool) {
 	if method != http.MethodPost || len(params) == 0 {
 		return nil, false
 	}
@@ -299,28 +624,3 @@ func prepareCommandBody(method string, params map[string]string) (*strings.Reade
 
 	return strings.NewReader(requestBody.Encode()), true
 }
-
-func (r *deviceCommandResource) executeCommand(req *http.Request, expectedStatus int) ([]byte, error) {
-	switch expectedStatus {
-	case http.StatusAccepted:
-		return r.client.RequestResponse202(req)
-	case http.StatusNoContent:
-		return r.client.RequestResponse204(req)
-	default:
-		return r.client.RequestResponse200(req)
-	}
-}
-
-func (r *deviceCommandResource) updateCreateState(ctx context.Context, plan *deviceCommandResourceModel, commandKey string, expectedStatus int, body []byte, resp *resource.CreateResponse) {
-	plan.StatusCode = types.Int64Value(int64(expectedStatus))
-	if len(body) > 0 {
-		plan.Response = types.StringValue(string(body))
-	} else {
-		plan.Response = types.StringNull()
-	}
-
-	plan.ID = types.StringValue(fmt.Sprintf(deviceCommandIDFormatTemplate, plan.DeviceID.ValueString(), commandKey, time.Now().UTC().Unix()))
-
-	diags := resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
-}
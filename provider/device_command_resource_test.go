@@ -118,6 +118,30 @@ func TestAccDeviceCommandResource_InvalidCommand(t *testing.T) {
 	})
 }
 
+// TestAccDeviceCommandResource_EmptyConfirmation tests that an empty device
+// confirmation does not satisfy the guard for destructive commands.
+func TestAccDeviceCommandResource_EmptyConfirmation(t *testing.T) {
+	testAccPreCheck(t)
+	deviceID := testAccRequireEnv(t, "SIMPLEMDM_DEVICE_ID")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+resource "simplemdm_device_command" "test" {
+  device_id             = "%s"
+  command               = "wipe"
+  confirm_device_serial = ""
+}
+`, deviceID),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Length`),
+			},
+		},
+	})
+}
+
 // testAccDeviceCommandResourceConfig returns a test configuration for a device command
 // without parameters.
 func testAccDeviceCommandResourceConfig(deviceID, command string) string {
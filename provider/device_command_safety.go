@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// deviceCommandTarget holds the settings that simplemdm_device_command
+// resources and actions share, so both validate and guard commands the same
+// way.
+type deviceCommandTarget struct {
+	DeviceID            types.String
+	Command             types.String
+	Parameters          types.Map
+	ConfirmDeviceSerial types.String
+	ConfirmDeviceName   types.String
+}
+
+// validateDeviceCommandConfig checks the parameters of the command and that
+// destructive commands name the device they expect.
+func validateDeviceCommandConfig(ctx context.Context, target deviceCommandTarget) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if target.Command.IsUnknown() || target.Command.IsNull() {
+		return diags
+	}
+
+	command := target.Command.ValueString()
+	spec, ok := deviceCommandCatalog[command]
+	if !ok {
+		return diags
+	}
+
+	diags.Append(validateCommandParameters(ctx, command, spec, target.Parameters)...)
+
+	if spec.destructive && !hasDeviceConfirmation(target.ConfirmDeviceSerial) && !hasDeviceConfirmation(target.ConfirmDeviceName) {
+		diags.AddAttributeError(
+			path.Root("confirm_device_serial"),
+			"Missing device confirmation",
+			fmt.Sprintf("The %q command is destructive. Set confirm_device_serial or confirm_device_name to the serial number or name of the target device; "+
+				"the provider checks it against the live device before sending the command.", command),
+		)
+	}
+
+	return diags
+}
+
+// hasDeviceConfirmation reports whether a confirmation may name the device.
+// Unknown values are checked once they are known, when the command is sent.
+func hasDeviceConfirmation(value types.String) bool {
+	return value.IsUnknown() || (!value.IsNull() && value.ValueString() != "")
+}
+
+// planDeviceCommand validates a command that is about to be planned again,
+// now that values unknown during validation may be known, and warns about
+// destructive commands. client may be nil before the provider is configured.
+func planDeviceCommand(ctx context.Context, client *simplemdm.Client, settings providerSettings, target deviceCommandTarget) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if target.Command.IsUnknown() {
+		return diags
+	}
+
+	command := target.Command.ValueString()
+	spec, ok := deviceCommandCatalog[command]
+	if !ok {
+		return diags
+	}
+
+	diags.Append(validateCommandParameters(ctx, command, spec, target.Parameters)...)
+	if diags.HasError() || !spec.destructive {
+		return diags
+	}
+
+	if client != nil && !checkDestructiveCommandAllowed(settings, command, &diags) {
+		return diags
+	}
+
+	deviceID := target.DeviceID.ValueString()
+	if target.DeviceID.IsUnknown() {
+		deviceID = "(known after apply)"
+	}
+
+	diags.AddWarning(
+		"Destructive device command planned",
+		fmt.Sprintf("Applying this plan sends the destructive %q command to device %s. This cannot be undone.", command, deviceID),
+	)
+
+	return diags
+}
+
+// authorizeDeviceCommand checks, right before sending, that a destructive
+// command is allowed and targets the expected device.
+func authorizeDeviceCommand(ctx context.Context, client *simplemdm.Client, settings providerSettings, target deviceCommandTarget, spec deviceCommandSpec, diags *diag.Diagnostics) bool {
+	if !spec.destructive {
+		return true
+	}
+
+	if !checkDestructiveCommandAllowed(settings, target.Command.ValueString(), diags) {
+		return false
+	}
+
+	return confirmDevice(ctx, client, target.DeviceID.ValueString(), target.ConfirmDeviceSerial.ValueString(), target.ConfirmDeviceName.ValueString(), diags)
+}
+
+// destructiveDeviceCommands returns the commands of deviceCommandCatalog that
+// erase data or remove a device from management, sorted by name.
+func destructiveDeviceCommands() []string {
+	commands := make([]string, 0)
+	for key, spec := range deviceCommandCatalog {
+		if spec.destructive {
+			commands = append(commands, key)
+		}
+	}
+	sort.Strings(commands)
+
+	return commands
+}
+
+// checkDestructiveCommandAllowed reports an error unless the provider
+// configuration allows command through allowed_destructive_commands.
+func checkDestructiveCommandAllowed(settings providerSettings, command string, diags *diag.Diagnostics) bool {
+	if settings.destructiveCommandAllowed(command) {
+		return true
+	}
+
+	diags.AddAttributeError(
+		path.Root("command"),
+		"Destructive device command not allowed",
+		fmt.Sprintf("The %q command is destructive and is not listed in the provider's allowed_destructive_commands. "+
+			"Add it to allowed_destructive_commands in the provider configuration to permit it.", command),
+	)
+
+	return false
+}
+
+// confirmDevice looks up the device and verifies it matches the serial number
+// and name the configuration expects, so a mistyped device_id cannot target
+// another device. At least one of them must be set.
+func confirmDevice(ctx context.Context, client *simplemdm.Client, deviceID, expectedSerial, expectedName string, diags *diag.Diagnostics) bool {
+	if expectedSerial == "" && expectedName == "" {
+		diags.AddAttributeError(
+			path.Root("confirm_device_serial"),
+			"Missing device confirmation",
+			fmt.Sprintf("Device %s was not sent the command: destructive commands require confirm_device_serial or confirm_device_name to name the target device.", deviceID),
+		)
+		return false
+	}
+
+	device, err := simplemdmext.GetDevice(ctx, client, deviceID, false)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("device_id"),
+			"Unable to confirm target device",
+			fmt.Sprintf("Could not read device %s to confirm it before sending a destructive command: %s", deviceID, err),
+		)
+		return false
+	}
+
+	if err := matchDevice(device.Data.Attributes, expectedSerial, expectedName); err != nil {
+		diags.AddAttributeError(
+			path.Root("device_id"),
+			"Target device does not match confirmation",
+			fmt.Sprintf("Device %s was not sent the command: %s. Check that device_id refers to the intended device.", deviceID, err),
+		)
+		return false
+	}
+
+	return true
+}
+
+// matchDevice compares the live device attributes with the expected serial
+// number and name. Empty expectations are not checked.
+func matchDevice(attributes map[string]any, expectedSerial, expectedName string) error {
+	flat := simplemdmext.FlattenAttributes(attributes)
+
+	if expectedSerial != "" && !strings.EqualFold(flat["serial_number"], expectedSerial) {
+		return fmt.Errorf("expected serial number %q, but the device reports %q", expectedSerial, flat["serial_number"])
+	}
+
+	if expectedName != "" && flat["name"] != expectedName && flat["device_name"] != expectedName {
+		return fmt.Errorf("expected name %q, but the device is named %q", expectedName, flat["name"])
+	}
+
+	return nil
+}
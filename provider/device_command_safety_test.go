@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// resourceTestValue builds an object value for the schema of r from the given
+// attribute values; every other attribute is null.
+func resourceTestValue(ctx context.Context, t *testing.T, r resource.Resource, values map[string]tftypes.Value) (resourceschema.Schema, tftypes.Value) {
+	t.Helper()
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("resource schema is not an object")
+	}
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+			continue
+		}
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	return schemaResp.Schema, tftypes.NewValue(objectType, attributes)
+}
+
+// newDeviceCommandTestServer serves device 1 with the given serial number and
+// name and records the device commands it receives.
+func newDeviceCommandTestServer(t *testing.T, serial, name string, commands *[]string) *simplemdm.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/api/v1/devices/1" {
+			_, _ = w.Write([]byte(`{"data":{"type":"device","id":1,"attributes":{"name":"` + name + `","serial_number":"` + serial + `"}}}`))
+			return
+		}
+
+		*commands = append(*commands, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	endpoint, err := simplemdmext.ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := simplemdmext.DefaultClientOptions()
+	options.Endpoint = endpoint
+	client, err := simplemdmext.NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return client
+}
+
+func createDeviceCommand(ctx context.Context, t *testing.T, client *simplemdm.Client, settings providerSettings, values map[string]tftypes.Value) *resource.CreateResponse {
+	t.Helper()
+
+	r := &deviceCommandResource{client: client, settings: settings}
+	schema, plan := resourceTestValue(ctx, t, r, values)
+
+	resp := &resource.CreateResponse{
+		State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(plan.Type(), nil)},
+	}
+	r.Create(ctx, resource.CreateRequest{Plan: tfsdk.Plan{Schema: schema, Raw: plan}}, resp)
+
+	return resp
+}
+
+func TestDeviceCommandCreateConfirmsDestructiveCommands(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		allowed     []string
+		serial      string
+		wantError   string
+		wantCommand bool
+	}{
+		{name: "not allowed", serial: "C02ABC", wantError: "Destructive device command not allowed"},
+		{name: "serial mismatch", allowed: []string{"wipe"}, serial: "C02XYZ", wantError: "Target device does not match confirmation"},
+		{name: "empty confirmation", allowed: []string{"wipe"}, serial: "", wantError: "Missing device confirmation"},
+		{name: "confirmed", allowed: []string{"wipe"}, serial: "c02abc", wantCommand: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			client := newDeviceCommandTestServer(t, "C02ABC", "Laptop", &commands)
+
+			settings := providerSettings{allowedDestructiveCommands: map[string]struct{}{}}
+			for _, command := range tt.allowed {
+				settings.allowedDestructiveCommands[command] = struct{}{}
+			}
+			resp := createDeviceCommand(ctx, t, client, settings, map[string]tftypes.Value{
+				"device_id":             tftypes.NewValue(tftypes.String, "1"),
+				"command":               tftypes.NewValue(tftypes.String, "wipe"),
+				"confirm_device_serial": tftypes.NewValue(tftypes.String, tt.serial),
+			})
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("expected %q, got %v", tt.wantError, resp.Diagnostics)
+				}
+			} else if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if sent := len(commands) > 0; sent != tt.wantCommand {
+				t.Fatalf("command sent = %v, want %v (%v)", sent, tt.wantCommand, commands)
+			}
+		})
+	}
+}
+
+func TestDeviceCommandValidateConfigRequiresConfirmation(t *testing.T) {
+	ctx := context.Background()
+	r := &deviceCommandResource{}
+
+	for command, wantError := range map[string]bool{"wipe": true, "delete_user": true, "refresh": false} {
+		schema, config := resourceTestValue(ctx, t, r, map[string]tftypes.Value{
+			"device_id": tftypes.NewValue(tftypes.String, "1"),
+			"command":   tftypes.NewValue(tftypes.String, command),
+		})
+
+		resp := &resource.ValidateConfigResponse{}
+		r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schema, Raw: config}}, resp)
+
+		if resp.Diagnostics.HasError() != wantError {
+			t.Errorf("%s: unexpected diagnostics %v", command, resp.Diagnostics)
+		}
+	}
+
+	schema, config := resourceTestValue(ctx, t, r, map[string]tftypes.Value{
+		"device_id":             tftypes.NewValue(tftypes.String, "1"),
+		"command":               tftypes.NewValue(tftypes.String, "wipe"),
+		"confirm_device_serial": tftypes.NewValue(tftypes.String, ""),
+	})
+
+	resp := &resource.ValidateConfigResponse{}
+	r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schema, Raw: config}}, resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("wipe with an empty confirm_device_serial: expected an error")
+	}
+}
+
+func TestDeviceCommandModifyPlanWarnsAboutDestructiveCommands(t *testing.T) {
+	ctx := context.Background()
+
+	var commands []string
+	client := newDeviceCommandTestServer(t, "C02ABC", "Laptop", &commands)
+	r := &deviceCommandResource{
+		client:   client,
+		settings: providerSettings{allowedDestructiveCommands: map[string]struct{}{"unenroll": {}}},
+	}
+	schema, plan := resourceTestValue(ctx, t, r, map[string]tftypes.Value{
+		"device_id":           tftypes.NewValue(tftypes.String, "1"),
+		"command":             tftypes.NewValue(tftypes.String, "unenroll"),
+		"confirm_device_name": tftypes.NewValue(tftypes.String, "Laptop"),
+	})
+
+	resp := &resource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: schema, Raw: plan}}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{
+		Plan:  tfsdk.Plan{Schema: schema, Raw: plan},
+		State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(plan.Type(), nil)},
+	}, resp)
+
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning, got %v", resp.Diagnostics)
+	}
+	if detail := resp.Diagnostics.Warnings()[0].Detail(); !strings.Contains(detail, "unenroll") || !strings.Contains(detail, "device 1") {
+		t.Fatalf("warning does not name the command and device: %s", detail)
+	}
+	if len(commands) != 0 {
+		t.Fatalf("planning must not send commands: %v", commands)
+	}
+}
+
+func TestMatchDevice(t *testing.T) {
+	attributes := map[string]any{"name": "Laptop", "device_name": "Jane's MacBook", "serial_number": "C02ABC"}
+
+	tests := []struct {
+		serial, name string
+		wantErr      bool
+	}{
+		{serial: "C02ABC"},
+		{serial: "c02abc", name: "Laptop"},
+		{name: "Jane's MacBook"},
+		{serial: "C02XYZ", wantErr: true},
+		{name: "Desktop", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := matchDevice(attributes, tt.serial, tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("matchDevice(%q, %q) error = %v, wantErr %v", tt.serial, tt.name, err, tt.wantErr)
+		}
+	}
+}
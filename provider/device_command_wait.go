@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// waitConditionCheckedIn is met once the device checks in after the
+	// command was sent.
+	waitConditionCheckedIn = "checked_in"
+	// waitConditionOSVersionChanged is met once the device reports an OS
+	// version different from the one before the command.
+	waitConditionOSVersionChanged = "os_version_changed"
+
+	defaultCommandWaitTimeout      = 600
+	defaultCommandWaitPollInterval = 15
+)
+
+// deviceCommandWaitModel maps the wait_for block.
+type deviceCommandWaitModel struct {
+	Timeout      types.Int64  `tfsdk:"timeout"`
+	PollInterval types.Int64  `tfsdk:"poll_interval"`
+	Condition    types.String `tfsdk:"condition"`
+}
+
+func deviceCommandWaitBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Waits after sending the command until the device reports the expected outcome, so later resources can depend on it. Without this block the command is sent and not followed up.",
+		Attributes: map[string]schema.Attribute{
+			"timeout": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum time in seconds to wait for the outcome, defaults to 600. The apply fails when it expires.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"poll_interval": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Time in seconds between device lookups, defaults to 15.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"condition": schema.StringAttribute{
+				Optional:    true,
+				Description: "Outcome to wait for: checked_in waits until the device reports a last_seen_at no earlier than the time the command was accepted, os_version_changed until it reports a different OS version. Defaults to os_version_changed for update_os and checked_in otherwise. The API does not report the status of individual commands, so a check-in is taken as the device having processed the command.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(waitConditionCheckedIn, waitConditionOSVersionChanged),
+				},
+			},
+		},
+	}
+}
+
+// deviceCommandWait is the resolved wait_for configuration.
+type deviceCommandWait struct {
+	timeout      time.Duration
+	pollInterval time.Duration
+	condition    string
+}
+
+func newDeviceCommandWait(model deviceCommandWaitModel, command string) deviceCommandWait {
+	wait := deviceCommandWait{
+		timeout:      defaultCommandWaitTimeout * time.Second,
+		pollInterval: defaultCommandWaitPollInterval * time.Second,
+		condition:    waitConditionCheckedIn,
+	}
+
+	if command == "update_os" {
+		wait.condition = waitConditionOSVersionChanged
+	}
+
+	if !model.Timeout.IsNull() && !model.Timeout.IsUnknown() {
+		wait.timeout = time.Duration(model.Timeout.ValueInt64()) * time.Second
+	}
+
+	if !model.PollInterval.IsNull() && !model.PollInterval.IsUnknown() {
+		wait.pollInterval = time.Duration(model.PollInterval.ValueInt64()) * time.Second
+	}
+
+	if !model.Condition.IsNull() && !model.Condition.IsUnknown() {
+		wait.condition = model.Condition.ValueString()
+	}
+
+	return wait
+}
+
+// deviceSnapshot holds the device details the wait conditions compare.
+type deviceSnapshot struct {
+	LastSeenAt string
+	OSVersion  string
+}
+
+func snapshotDevice(ctx context.Context, client *simplemdm.Client, deviceID string) (deviceSnapshot, error) {
+	device, err := simplemdmext.GetDevice(ctx, client, deviceID, false)
+	if err != nil {
+		return deviceSnapshot{}, err
+	}
+
+	attributes := simplemdmext.FlattenAttributes(device.Data.Attributes)
+
+	return deviceSnapshot{
+		LastSeenAt: attributes["last_seen_at"],
+		OSVersion:  attributes["os_version"],
+	}, nil
+}
+
+// satisfies reports whether the snapshot shows the condition compared with
+// the baseline taken before the command was sent. A check-in only counts when
+// it is reported after acceptedAt, the time the API accepted the command, as
+// an earlier one cannot have processed it.
+func (s deviceSnapshot) satisfies(condition string, baseline deviceSnapshot, acceptedAt time.Time) bool {
+	switch condition {
+	case waitConditionOSVersionChanged:
+		return s.OSVersion != "" && s.OSVersion != baseline.OSVersion
+	default:
+		return seenAfter(s.LastSeenAt, baseline.LastSeenAt) && seenSince(s.LastSeenAt, acceptedAt)
+	}
+}
+
+// seenSince reports whether the last_seen_at value current is not earlier
+// than at. last_seen_at only has second precision, so a check-in within the
+// same second as at counts.
+func seenSince(current string, at time.Time) bool {
+	if current == "" {
+		return false
+	}
+
+	currentTime, err := time.Parse(time.RFC3339, current)
+	if err != nil {
+		return true
+	}
+
+	return !currentTime.Before(at.Truncate(time.Second))
+}
+
+// seenAfter reports whether the last_seen_at value current is later than
+// previous.
+func seenAfter(current, previous string) bool {
+	if current == "" {
+		return false
+	}
+	if previous == "" {
+		return true
+	}
+
+	currentTime, currentErr := time.Parse(time.RFC3339, current)
+	previousTime, previousErr := time.Parse(time.RFC3339, previous)
+	if currentErr != nil || previousErr != nil {
+		return current != previous
+	}
+
+	return currentTime.After(previousTime)
+}
+
+// waitForDevice polls the device until it satisfies the wait condition, the
+// timeout expires or ctx is cancelled. The last snapshot observed is returned
+// in every case.
+func waitForDevice(ctx context.Context, client *simplemdm.Client, deviceID string, wait deviceCommandWait, baseline deviceSnapshot, acceptedAt time.Time) (deviceSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, wait.timeout)
+	defer cancel()
+
+	observed := baseline
+	ticker := time.NewTicker(wait.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return observed, fmt.Errorf("waiting for device %s to reach %s was cancelled", deviceID, wait.condition)
+			}
+			return observed, fmt.Errorf("device %s did not reach %s within %s", deviceID, wait.condition, wait.timeout)
+		case <-ticker.C:
+		}
+
+		snapshot, err := snapshotDevice(ctx, client, deviceID)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return observed, err
+		}
+
+		observed = snapshot
+		if observed.satisfies(wait.condition, baseline, acceptedAt) {
+			return observed, nil
+		}
+	}
+}
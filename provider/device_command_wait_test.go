@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+)
+
+// newDeviceWaitTestServer serves device 1, reporting the given snapshots one
+// lookup after another and repeating the last one.
+func newDeviceWaitTestServer(t *testing.T, snapshots ...deviceSnapshot) (*simplemdm.Client, *atomic.Int32) {
+	t.Helper()
+
+	var lookups atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		index := int(lookups.Add(1)) - 1
+		if index >= len(snapshots) {
+			index = len(snapshots) - 1
+		}
+		snapshot := snapshots[index]
+		_, _ = w.Write([]byte(`{"data":{"type":"device","id":1,"attributes":{"last_seen_at":"` + snapshot.LastSeenAt + `","os_version":"` + snapshot.OSVersion + `"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	endpoint, err := simplemdmext.ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := simplemdmext.DefaultClientOptions()
+	options.Endpoint = endpoint
+	client, err := simplemdmext.NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return client, &lookups
+}
+
+func TestWaitForDeviceReturnsOnceConditionIsMet(t *testing.T) {
+	baseline := deviceSnapshot{LastSeenAt: "2024-05-01T10:00:00Z", OSVersion: "14.4"}
+	client, lookups := newDeviceWaitTestServer(t,
+		baseline,
+		deviceSnapshot{LastSeenAt: "2024-05-01T10:05:00Z", OSVersion: "14.4"},
+		deviceSnapshot{LastSeenAt: "2024-05-01T10:20:00Z", OSVersion: "14.5"},
+	)
+
+	wait := deviceCommandWait{timeout: 5 * time.Second, pollInterval: time.Millisecond, condition: waitConditionOSVersionChanged}
+	observed, err := waitForDevice(context.Background(), client, "1", wait, baseline, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if observed.OSVersion != "14.5" || observed.LastSeenAt != "2024-05-01T10:20:00Z" {
+		t.Fatalf("unexpected snapshot: %+v", observed)
+	}
+	if got := lookups.Load(); got != 3 {
+		t.Fatalf("expected 3 lookups, got %d", got)
+	}
+}
+
+func TestWaitForDeviceTimesOut(t *testing.T) {
+	baseline := deviceSnapshot{LastSeenAt: "2024-05-01T10:00:00Z", OSVersion: "14.4"}
+	client, _ := newDeviceWaitTestServer(t, baseline)
+
+	wait := deviceCommandWait{timeout: 50 * time.Millisecond, pollInterval: 5 * time.Millisecond, condition: waitConditionCheckedIn}
+	observed, err := waitForDevice(context.Background(), client, "1", wait, baseline, time.Time{})
+	if err == nil || !strings.Contains(err.Error(), "did not reach checked_in") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if observed != baseline {
+		t.Fatalf("expected the last snapshot to be returned, got %+v", observed)
+	}
+}
+
+func TestWaitForDeviceReportsCancellation(t *testing.T) {
+	baseline := deviceSnapshot{LastSeenAt: "2024-05-01T10:00:00Z", OSVersion: "14.4"}
+	client, _ := newDeviceWaitTestServer(t, baseline)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	wait := deviceCommandWait{timeout: 5 * time.Second, pollInterval: 5 * time.Millisecond, condition: waitConditionCheckedIn}
+	_, err := waitForDevice(ctx, client, "1", wait, baseline, time.Time{})
+	if err == nil || !strings.Contains(err.Error(), "was cancelled") {
+		t.Fatalf("expected a cancellation error, got %v", err)
+	}
+}
+
+func TestDeviceSnapshotSatisfies(t *testing.T) {
+	baseline := deviceSnapshot{LastSeenAt: "2024-05-01T10:00:00Z", OSVersion: "14.4"}
+	acceptedAt := time.Date(2024, time.May, 1, 10, 0, 30, 400*int(time.Millisecond), time.UTC)
+
+	tests := []struct {
+		name      string
+		condition string
+		snapshot  deviceSnapshot
+		want      bool
+	}{
+		{name: "not checked in", condition: waitConditionCheckedIn, snapshot: baseline},
+		{name: "checked in", condition: waitConditionCheckedIn, snapshot: deviceSnapshot{LastSeenAt: "2024-05-01T10:00:31Z"}, want: true},
+		{name: "checked in the second the command was accepted", condition: waitConditionCheckedIn, snapshot: deviceSnapshot{LastSeenAt: "2024-05-01T10:00:30Z"}, want: true},
+		{name: "checked in before the command was accepted", condition: waitConditionCheckedIn, snapshot: deviceSnapshot{LastSeenAt: "2024-05-01T10:00:10Z"}},
+		{name: "checked in with offset", condition: waitConditionCheckedIn, snapshot: deviceSnapshot{LastSeenAt: "2024-05-01T11:30:00+01:00"}, want: true},
+		{name: "earlier check in", condition: waitConditionCheckedIn, snapshot: deviceSnapshot{LastSeenAt: "2024-05-01T10:30:00+01:00"}},
+		{name: "os unchanged", condition: waitConditionOSVersionChanged, snapshot: deviceSnapshot{LastSeenAt: "2024-05-01T11:00:00Z", OSVersion: "14.4"}},
+		{name: "os missing", condition: waitConditionOSVersionChanged, snapshot: deviceSnapshot{}},
+		{name: "os changed", condition: waitConditionOSVersionChanged, snapshot: deviceSnapshot{OSVersion: "14.5"}, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.snapshot.satisfies(tt.condition, baseline, acceptedAt); got != tt.want {
+			t.Errorf("%s: satisfies = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewDeviceCommandWaitDefaults(t *testing.T) {
+	wait := newDeviceCommandWait(deviceCommandWaitModel{}, "update_os")
+	if wait.condition != waitConditionOSVersionChanged || wait.timeout != 10*time.Minute || wait.pollInterval != 15*time.Second {
+		t.Fatalf("unexpected defaults for update_os: %+v", wait)
+	}
+
+	if wait := newDeviceCommandWait(deviceCommandWaitModel{}, "restart"); wait.condition != waitConditionCheckedIn {
+		t.Fatalf("expected restart to wait for a check-in, got %q", wait.condition)
+	}
+}
@@ -118,7 +118,7 @@ func (d *deviceInstalledAppsDataSource) Configure(_ context.Context, req datasou
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -128,5 +128,5 @@ func (d *deviceInstalledAppsDataSource) Configure(_ context.Context, req datasou
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
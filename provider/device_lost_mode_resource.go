@@ -0,0 +1,367 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &deviceLostModeResource{}
+	_ resource.ResourceWithConfigure      = &deviceLostModeResource{}
+	_ resource.ResourceWithImportState    = &deviceLostModeResource{}
+	_ resource.ResourceWithValidateConfig = &deviceLostModeResource{}
+)
+
+// lostModeEnabledAttribute is the device attribute reporting whether lost
+// mode is active.
+const lostModeEnabledAttribute = "lost_mode_enabled"
+
+var (
+	enableLostModeSpec         = deviceCommandSpec{method: http.MethodPost, pathTemplate: "lost_mode", expectedStatus: http.StatusAccepted}
+	disableLostModeSpec        = deviceCommandSpec{method: http.MethodDelete, pathTemplate: "lost_mode", expectedStatus: http.StatusAccepted}
+	playLostModeSoundSpec      = deviceCommandSpec{method: http.MethodPost, pathTemplate: "lost_mode/play_sound", expectedStatus: http.StatusAccepted}
+	updateLostModeLocationSpec = deviceCommandSpec{method: http.MethodPost, pathTemplate: "lost_mode/update_location", expectedStatus: http.StatusAccepted}
+)
+
+type deviceLostModeResource struct {
+	client *simplemdm.Client
+}
+
+type deviceLostModeResourceModel struct {
+	ID                     types.String `tfsdk:"id"`
+	DeviceID               types.String `tfsdk:"device_id"`
+	Message                types.String `tfsdk:"message"`
+	PhoneNumber            types.String `tfsdk:"phone_number"`
+	Footnote               types.String `tfsdk:"footnote"`
+	PlaySoundTriggers      types.Map    `tfsdk:"play_sound_triggers"`
+	UpdateLocationTriggers types.Map    `tfsdk:"update_location_triggers"`
+	Enabled                types.Bool   `tfsdk:"enabled"`
+	LocationLatitude       types.String `tfsdk:"location_latitude"`
+	LocationLongitude      types.String `tfsdk:"location_longitude"`
+	LocationAccuracy       types.String `tfsdk:"location_accuracy"`
+	LocationUpdatedAt      types.String `tfsdk:"location_updated_at"`
+}
+
+func DeviceLostModeResource() resource.Resource {
+	return &deviceLostModeResource{}
+}
+
+func (r *deviceLostModeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_lost_mode"
+}
+
+func (r *deviceLostModeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Puts a supervised device into Lost Mode while the resource exists. Destroying the resource disables Lost Mode.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "ID of the device, same as device_id.",
+			},
+			"device_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Description: "Identifier of the device to put into Lost Mode.",
+			},
+			"message": schema.StringAttribute{
+				Optional:    true,
+				Description: "Message shown on the lock screen. Either this or phone_number is required. The API does not report the lock screen text, so after an import the configured text is recorded without enabling Lost Mode again.",
+			},
+			"phone_number": schema.StringAttribute{
+				Optional:    true,
+				Description: "Phone number shown on the lock screen. Either this or message is required.",
+			},
+			"footnote": schema.StringAttribute{
+				Optional:    true,
+				Description: "Footnote shown on the lock screen.",
+			},
+			"play_sound_triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that make the device play the Lost Mode sound whenever any of them changes.",
+			},
+			"update_location_triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that request a new location from the device whenever any of them changes.",
+			},
+			"enabled": schema.BoolAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Whether the device reports Lost Mode as active. It becomes true once the device has processed the command. When Lost Mode is later disabled outside Terraform, the resource is removed from state and enabled again on the next apply.",
+			},
+			"location_latitude": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Latitude of the last known location of the device.",
+			},
+			"location_longitude": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Longitude of the last known location of the device.",
+			},
+			"location_accuracy": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Accuracy in meters of the last known location.",
+			},
+			"location_updated_at": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Time at which the last known location was reported.",
+			},
+		},
+	}
+}
+
+func (r *deviceLostModeResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		return
+	}
+
+	r.client = data.client
+}
+
+func (r *deviceLostModeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config deviceLostModeResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Message.IsNull() && config.PhoneNumber.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("message"),
+			"Missing Lost Mode message",
+			"Set message, phone_number or both; the device shows them on its lock screen.",
+		)
+	}
+}
+
+func (r *deviceLostModeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("device_id"), req, resp)
+}
+
+func (r *deviceLostModeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
+	if r.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "Unable to create Lost Mode because the client was not configured")
+		return
+	}
+
+	var plan deviceLostModeResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := plan.DeviceID.ValueString()
+	if _, err := sendDeviceCommand(ctx, r.client, deviceID, enableLostModeSpec, lostModeParameters(plan)); err != nil {
+		resp.Diagnostics.AddError(
+			"Error enabling Lost Mode",
+			"Could not enable Lost Mode on device "+deviceID+": "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(deviceID)
+
+	if err := r.readDevice(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading SimpleMDM device",
+			"Lost Mode was enabled, but device "+deviceID+" could not be read: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *deviceLostModeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "Unable to read Lost Mode because the client was not configured")
+		return
+	}
+
+	var state deviceLostModeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	wasEnabled := state.Enabled.ValueBool()
+	if err := r.readDevice(ctx, &state); err != nil {
+		if isNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading SimpleMDM device",
+			"Could not read device "+state.DeviceID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Lost Mode that the device reported as active and that has since been
+	// disabled outside Terraform is gone; the next apply enables it again.
+	// A device that has not processed the command yet is left alone.
+	if wasEnabled && !state.Enabled.ValueBool() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *deviceLostModeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
+	if r.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "Unable to update Lost Mode because the client was not configured")
+		return
+	}
+
+	var plan, state deviceLostModeResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := plan.DeviceID.ValueString()
+	plan.ID = state.ID
+
+	// SimpleMDM has no endpoint to change the lock screen text; enabling Lost
+	// Mode again replaces it. Imported Lost Mode has no text in state because
+	// the API does not report it, so the configured text is only recorded.
+	imported := state.Message.IsNull() && state.PhoneNumber.IsNull()
+	if !imported && (!plan.Message.Equal(state.Message) || !plan.PhoneNumber.Equal(state.PhoneNumber) || !plan.Footnote.Equal(state.Footnote)) {
+		if _, err := sendDeviceCommand(ctx, r.client, deviceID, enableLostModeSpec, lostModeParameters(plan)); err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating Lost Mode",
+				"Could not update the Lost Mode message of device "+deviceID+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if !plan.PlaySoundTriggers.Equal(state.PlaySoundTriggers) {
+		if _, err := sendDeviceCommand(ctx, r.client, deviceID, playLostModeSoundSpec, nil); err != nil {
+			resp.Diagnostics.AddError(
+				"Error playing Lost Mode sound",
+				"Could not play the Lost Mode sound on device "+deviceID+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if !plan.UpdateLocationTriggers.Equal(state.UpdateLocationTriggers) {
+		if _, err := sendDeviceCommand(ctx, r.client, deviceID, updateLostModeLocationSpec, nil); err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating Lost Mode location",
+				"Could not request the location of device "+deviceID+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// The computed attributes keep their planned prior values. The device
+	// processes the commands asynchronously, so the next refresh reports
+	// their outcome.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *deviceLostModeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
+	if r.client == nil {
+		resp.Diagnostics.AddError("Provider not configured", "Unable to delete Lost Mode because the client was not configured")
+		return
+	}
+
+	var state deviceLostModeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deviceID := state.DeviceID.ValueString()
+	if _, err := sendDeviceCommand(ctx, r.client, deviceID, disableLostModeSpec, nil); err != nil && !isNotFoundError(err) {
+		resp.Diagnostics.AddError(
+			"Error disabling Lost Mode",
+			"Could not disable Lost Mode on device "+deviceID+": "+err.Error(),
+		)
+	}
+}
+
+// readDevice refreshes the Lost Mode status and last known location in model.
+func (r *deviceLostModeResource) readDevice(ctx context.Context, model *deviceLostModeResourceModel) error {
+	device, err := simplemdmext.GetDevice(ctx, r.client, model.DeviceID.ValueString(), false)
+	if err != nil {
+		return err
+	}
+
+	attributes := simplemdmext.FlattenAttributes(device.Data.Attributes)
+
+	model.Enabled = types.BoolValue(attributes[lostModeEnabledAttribute] == "true")
+	model.LocationLatitude = stringValueOrNull(attributes["location_latitude"])
+	model.LocationLongitude = stringValueOrNull(attributes["location_longitude"])
+	model.LocationAccuracy = stringValueOrNull(attributes["location_accuracy"])
+	model.LocationUpdatedAt = stringValueOrNull(attributes["location_updated_at"])
+
+	return nil
+}
+
+// lostModeParameters returns the lock screen parameters set in model.
+func lostModeParameters(model deviceLostModeResourceModel) map[string]string {
+	params := map[string]string{}
+	for key, value := range map[string]types.String{
+		"message":      model.Message,
+		"phone_number": model.PhoneNumber,
+		"footnote":     model.Footnote,
+	} {
+		if value.ValueString() != "" {
+			params[key] = value.ValueString()
+		}
+	}
+
+	return params
+}
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newLostModeTestServer serves device 1, in Lost Mode while enabled is true,
+// and records the Lost Mode requests it receives together with their form
+// bodies.
+func newLostModeTestServer(t *testing.T, enabled *bool, requests *[]string) *simplemdm.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/api/v1/devices/1" {
+			_, _ = w.Write([]byte(`{"data":{"type":"device","id":1,"attributes":{"lost_mode_enabled":` + strconv.FormatBool(*enabled) + `,"location_latitude":"52.52","location_longitude":"13.405","location_accuracy":65,"location_updated_at":"2024-05-01T10:00:00Z"}}}`))
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		*requests = append(*requests, r.Method+" "+r.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	endpoint, err := simplemdmext.ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := simplemdmext.DefaultClientOptions()
+	options.Endpoint = endpoint
+	client, err := simplemdmext.NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return client
+}
+
+func TestDeviceLostModeLifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	enabled := true
+	var requests []string
+	client := newLostModeTestServer(t, &enabled, &requests)
+	r := &deviceLostModeResource{client: client}
+
+	schema, plan := resourceTestValue(ctx, t, r, map[string]tftypes.Value{
+		"device_id": tftypes.NewValue(tftypes.String, "1"),
+		"message":   tftypes.NewValue(tftypes.String, "Please return"),
+		"footnote":  tftypes.NewValue(tftypes.String, "Reward"),
+	})
+
+	createResp := &resource.CreateResponse{State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(plan.Type(), nil)}}
+	r.Create(ctx, resource.CreateRequest{Plan: tfsdk.Plan{Schema: schema, Raw: plan}}, createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", createResp.Diagnostics)
+	}
+
+	var created deviceLostModeResourceModel
+	createResp.State.Get(ctx, &created)
+	if !created.Enabled.ValueBool() || created.LocationLatitude.ValueString() != "52.52" || created.LocationAccuracy.ValueString() != "65" {
+		t.Fatalf("unexpected state after create: %+v", created)
+	}
+
+	updatedPlan := withAttributes(t, createResp.State.Raw, map[string]tftypes.Value{
+		"message":             tftypes.NewValue(tftypes.String, "Call me"),
+		"play_sound_triggers": triggersValue(map[string]string{"at": "1"}),
+	})
+	updateResp := &resource.UpdateResponse{State: tfsdk.State{Schema: schema, Raw: updatedPlan}}
+	r.Update(ctx, resource.UpdateRequest{Plan: tfsdk.Plan{Schema: schema, Raw: updatedPlan}, State: createResp.State}, updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", updateResp.Diagnostics)
+	}
+
+	deleteResp := &resource.DeleteResponse{State: updateResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: updateResp.State}, deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", deleteResp.Diagnostics)
+	}
+
+	want := []string{
+		"POST /api/v1/devices/1/lost_mode footnote=Reward&message=Please+return",
+		"POST /api/v1/devices/1/lost_mode footnote=Reward&message=Call+me",
+		"POST /api/v1/devices/1/lost_mode/play_sound ",
+		"DELETE /api/v1/devices/1/lost_mode ",
+	}
+	if !slices.Equal(requests, want) {
+		t.Fatalf("unexpected requests:\n got %q\nwant %q", requests, want)
+	}
+}
+
+func TestDeviceLostModeUpdateAfterImportRecordsLockScreenText(t *testing.T) {
+	ctx := context.Background()
+
+	enabled := true
+	var requests []string
+	client := newLostModeTestServer(t, &enabled, &requests)
+	r := &deviceLostModeResource{client: client}
+
+	schema, imported := resourceTestValue(ctx, t, r, map[string]tftypes.Value{
+		"id":        tftypes.NewValue(tftypes.String, "1"),
+		"device_id": tftypes.NewValue(tftypes.String, "1"),
+		"enabled":   tftypes.NewValue(tftypes.Bool, true),
+	})
+	plan := withAttributes(t, imported, map[string]tftypes.Value{
+		"message": tftypes.NewValue(tftypes.String, "Please return"),
+	})
+
+	resp := &resource.UpdateResponse{State: tfsdk.State{Schema: schema, Raw: plan}}
+	r.Update(ctx, resource.UpdateRequest{Plan: tfsdk.Plan{Schema: schema, Raw: plan}, State: tfsdk.State{Schema: schema, Raw: imported}}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var updated deviceLostModeResourceModel
+	resp.State.Get(ctx, &updated)
+	if updated.Message.ValueString() != "Please return" || !updated.Enabled.ValueBool() {
+		t.Fatalf("unexpected state after update: %+v", updated)
+	}
+	if len(requests) != 0 {
+		t.Fatalf("expected Lost Mode not to be enabled again, got %q", requests)
+	}
+}
+
+func TestDeviceLostModeReadRemovesDisabledLostMode(t *testing.T) {
+	ctx := context.Background()
+
+	enabled := false
+	var requests []string
+	r := &deviceLostModeResource{client: newLostModeTestServer(t, &enabled, &requests)}
+
+	tests := []struct {
+		name        string
+		wasEnabled  bool
+		wantRemoved bool
+	}{
+		{name: "disabled outside Terraform", wasEnabled: true, wantRemoved: true},
+		{name: "not processed yet", wasEnabled: false, wantRemoved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, state := resourceTestValue(ctx, t, r, map[string]tftypes.Value{
+				"id":        tftypes.NewValue(tftypes.String, "1"),
+				"device_id": tftypes.NewValue(tftypes.String, "1"),
+				"message":   tftypes.NewValue(tftypes.String, "Please return"),
+				"enabled":   tftypes.NewValue(tftypes.Bool, tt.wasEnabled),
+			})
+
+			resp := &resource.ReadResponse{State: tfsdk.State{Schema: schema, Raw: state}}
+			r.Read(ctx, resource.ReadRequest{State: tfsdk.State{Schema: schema, Raw: state}}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if removed := resp.State.Raw.IsNull(); removed != tt.wantRemoved {
+				t.Fatalf("removed = %t, want %t", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestDeviceLostModeRequiresConfiguredClient(t *testing.T) {
+	ctx := context.Background()
+	r := &deviceLostModeResource{}
+
+	schema, state := resourceTestValue(ctx, t, r, map[string]tftypes.Value{
+		"id":        tftypes.NewValue(tftypes.String, "1"),
+		"device_id": tftypes.NewValue(tftypes.String, "1"),
+	})
+
+	resp := &resource.ReadResponse{State: tfsdk.State{Schema: schema, Raw: state}}
+	r.Read(ctx, resource.ReadRequest{State: tfsdk.State{Schema: schema, Raw: state}}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error without a configured client")
+	}
+}
+
+func TestDeviceLostModeValidateConfigRequiresMessage(t *testing.T) {
+	ctx := context.Background()
+	r := &deviceLostModeResource{}
+
+	for _, tt := range []struct {
+		values    map[string]tftypes.Value
+		wantError bool
+	}{
+		{values: map[string]tftypes.Value{"footnote": tftypes.NewValue(tftypes.String, "Reward")}, wantError: true},
+		{values: map[string]tftypes.Value{"phone_number": tftypes.NewValue(tftypes.String, "+15555551234")}},
+	} {
+		tt.values["device_id"] = tftypes.NewValue(tftypes.String, "1")
+		schema, config := resourceTestValue(ctx, t, r, tt.values)
+
+		resp := &resource.ValidateConfigResponse{}
+		r.ValidateConfig(ctx, resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schema, Raw: config}}, resp)
+
+		if resp.Diagnostics.HasError() != tt.wantError {
+			t.Errorf("unexpected diagnostics for %v: %v", tt.values, resp.Diagnostics)
+		}
+	}
+}
@@ -124,7 +124,7 @@ func (d *deviceProfilesDataSource) Configure(_ context.Context, req datasource.C
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -134,5 +134,5 @@ func (d *deviceProfilesDataSource) Configure(_ context.Context, req datasource.C
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
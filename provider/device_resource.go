@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"strconv"
-	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
 	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
@@ -53,7 +52,7 @@ func (r *deviceResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -126,6 +125,10 @@ func (r *deviceResource) ImportState(ctx context.Context, req resource.ImportSta
 
 // Create a new resource
 func (r *deviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan deviceResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -216,7 +219,7 @@ func (r *deviceResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	apiDevice, err := simplemdmext.GetDevice(ctx, r.client, state.ID.ValueString(), true)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -241,6 +244,10 @@ func (r *deviceResource) Read(ctx context.Context, req resource.ReadRequest, res
 }
 
 func (r *deviceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan, state deviceResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -402,6 +409,21 @@ func (r *deviceResource) Update(ctx context.Context, req resource.UpdateRequest,
 		}
 	}
 
+	// Refresh state from API to populate computed attributes and relationships
+	apiDevice, err := simplemdmext.GetDevice(ctx, r.client, plan.ID.ValueString(), true)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading SimpleMDM device",
+			"Could not read SimpleMDM device "+plan.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.assignAPIValues(ctx, apiDevice, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -410,6 +432,10 @@ func (r *deviceResource) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *deviceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state deviceResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -419,7 +445,7 @@ func (r *deviceResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	// Delete existing device
 	err := r.client.DeviceDelete(state.ID.ValueString())
-	if err != nil {
+	if err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting SimpleMDM device",
 			"Could not delte device, unexpected error: "+err.Error(),
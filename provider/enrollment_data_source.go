@@ -44,7 +44,7 @@ func (d *enrollmentDataSource) Configure(_ context.Context, req datasource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -54,7 +54,7 @@ func (d *enrollmentDataSource) Configure(_ context.Context, req datasource.Confi
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 func (d *enrollmentDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
@@ -3,13 +3,13 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	neturl "net/url"
 	"strconv"
 	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 )
 
 type enrollmentResponse struct {
@@ -60,14 +60,14 @@ type enrollmentFlat struct {
 }
 
 func fetchEnrollment(ctx context.Context, client *simplemdm.Client, id string) (*enrollmentResponse, error) {
-	url := fmt.Sprintf("https://%s/api/v1/enrollments/%s", client.HostName, id)
+	url := simplemdmext.APIURL(client, "enrollments/%s", id)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := client.RequestResponse200(req)
+	body, err := simplemdmext.Do(client, req, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +81,7 @@ func fetchEnrollment(ctx context.Context, client *simplemdm.Client, id string) (
 }
 
 func createEnrollment(ctx context.Context, client *simplemdm.Client, payload enrollmentUpsertRequest) (*enrollmentResponse, error) {
-	endpoint := fmt.Sprintf("https://%s/api/v1/enrollments", client.HostName)
+	endpoint := simplemdmext.APIURL(client, "enrollments")
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
 	if err != nil {
@@ -105,7 +105,7 @@ func createEnrollment(ctx context.Context, client *simplemdm.Client, payload enr
 
 	req.URL.RawQuery = q.Encode()
 
-	body, err := client.RequestResponse201(req)
+	body, err := simplemdmext.Do(client, req, http.StatusCreated)
 	if err != nil {
 		return nil, err
 	}
@@ -119,19 +119,19 @@ func createEnrollment(ctx context.Context, client *simplemdm.Client, payload enr
 }
 
 func deleteEnrollment(ctx context.Context, client *simplemdm.Client, id string) error {
-	endpoint := fmt.Sprintf("https://%s/api/v1/enrollments/%s", client.HostName, id)
+	endpoint := simplemdmext.APIURL(client, "enrollments/%s", id)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
 	if err != nil {
 		return err
 	}
 
-	_, err = client.RequestResponse204(req)
+	_, err = simplemdmext.Do(client, req, http.StatusNoContent)
 	return err
 }
 
 func sendEnrollmentInvitation(ctx context.Context, client *simplemdm.Client, id string, contact string) error {
-	endpoint := fmt.Sprintf("https://%s/api/v1/enrollments/%s/invitations", client.HostName, id)
+	endpoint := simplemdmext.APIURL(client, "enrollments/%s/invitations", id)
 
 	form := neturl.Values{}
 	form.Set("contact", contact)
@@ -143,7 +143,7 @@ func sendEnrollmentInvitation(ctx context.Context, client *simplemdm.Client, id
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	_, err = client.RequestResponse200(req)
+	_, err = simplemdmext.Do(client, req, http.StatusOK)
 	return err
 }
 
@@ -171,49 +171,15 @@ func flattenEnrollment(response *enrollmentResponse) enrollmentFlat {
 	}
 }
 
-type enrollmentsListResponse struct {
-	Data    []enrollmentData `json:"data"`
-	HasMore bool             `json:"has_more"`
-}
-
-func listEnrollments(ctx context.Context, client *simplemdm.Client, startingAfter int) ([]enrollmentResponse, error) {
+func listEnrollments(ctx context.Context, client *simplemdm.Client) ([]enrollmentResponse, error) {
 	var allEnrollments []enrollmentResponse
-	limit := 100
 
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/enrollments?limit=%d", client.HostName, limit)
-		if startingAfter > 0 {
-			url += fmt.Sprintf("&starting_after=%d", startingAfter)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	cursor := simplemdmext.IntCursor(func(data enrollmentData) int { return data.ID })
+	for data, err := range simplemdmext.Paginate(ctx, client, "enrollments", simplemdmext.ListOptions{}, cursor) {
 		if err != nil {
 			return nil, err
 		}
-
-		body, err := client.RequestResponse200(req)
-		if err != nil {
-			return nil, err
-		}
-
-		var response enrollmentsListResponse
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, err
-		}
-
-		for _, data := range response.Data {
-			allEnrollments = append(allEnrollments, enrollmentResponse{Data: data})
-		}
-
-		if !response.HasMore {
-			break
-		}
-
-		if len(response.Data) > 0 {
-			startingAfter = response.Data[len(response.Data)-1].ID
-		} else {
-			break
-		}
+		allEnrollments = append(allEnrollments, enrollmentResponse{Data: data})
 	}
 
 	return allEnrollments, nil
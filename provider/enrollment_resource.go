@@ -51,7 +51,7 @@ func (r *enrollmentResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
@@ -61,7 +61,7 @@ func (r *enrollmentResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	r.client = client
+	r.client = data.client
 }
 
 func (r *enrollmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -133,6 +133,10 @@ func (r *enrollmentResource) Schema(_ context.Context, _ resource.SchemaRequest,
 }
 
 func (r *enrollmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	var plan enrollmentResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -210,6 +214,10 @@ func (r *enrollmentResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *enrollmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	var plan enrollmentResourceModel
 	var state enrollmentResourceModel
 
@@ -243,6 +251,10 @@ func (r *enrollmentResource) Update(ctx context.Context, req resource.UpdateRequ
 }
 
 func (r *enrollmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state enrollmentResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -250,7 +262,7 @@ func (r *enrollmentResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
-	if err := deleteEnrollment(ctx, r.client, state.ID.ValueString()); err != nil {
+	if err := deleteEnrollment(ctx, r.client, state.ID.ValueString()); err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError(
 			"Error deleting enrollment",
 			err.Error(),
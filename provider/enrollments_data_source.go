@@ -152,7 +152,7 @@ func (d *enrollmentsDataSource) Configure(_ context.Context, req datasource.Conf
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -162,10 +162,10 @@ func (d *enrollmentsDataSource) Configure(_ context.Context, req datasource.Conf
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 // fetchAllEnrollments retrieves all enrollments with pagination support
 func fetchAllEnrollments(ctx context.Context, client *simplemdm.Client) ([]enrollmentResponse, error) {
-	return listEnrollments(ctx, client, 0)
+	return listEnrollments(ctx, client)
 }
@@ -5,13 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"mime/multipart"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 )
 
 type managedConfigAttributes struct {
@@ -36,28 +35,12 @@ type managedConfigItemResponse struct {
 var errManagedConfigNotFound = errors.New("managed config not found")
 
 func fetchManagedConfig(ctx context.Context, client *simplemdm.Client, appID, configID string) (*managedConfigAPIResource, error) {
-	if client == nil {
-		return nil, errors.New("simplemdm client is not configured")
-	}
-
-	url := fmt.Sprintf("https://%s/api/v1/apps/%s/managed_configs", client.HostName, appID)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	configs, err := fetchAllManagedConfigs(ctx, client, appID)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := client.RequestResponse200(req)
-	if err != nil {
-		return nil, err
-	}
-
-	var response managedConfigListResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, err
-	}
-
-	for _, item := range response.Data {
+	for _, item := range configs {
 		if strconv.Itoa(item.ID) == configID {
 			return &item, nil
 		}
@@ -67,7 +50,7 @@ func fetchManagedConfig(ctx context.Context, client *simplemdm.Client, appID, co
 }
 
 func createManagedConfig(ctx context.Context, client *simplemdm.Client, appID, key, value, valueType string) (*managedConfigAPIResource, error) {
-	url := fmt.Sprintf("https://%s/api/v1/apps/%s/managed_configs", client.HostName, appID)
+	url := simplemdmext.APIURL(client, "apps/%s/managed_configs", appID)
 
 	payload := &bytes.Buffer{}
 	writer := multipart.NewWriter(payload)
@@ -95,7 +78,7 @@ func createManagedConfig(ctx context.Context, client *simplemdm.Client, appID, k
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	body, err := client.RequestResponse201(req)
+	body, err := simplemdmext.Do(client, req, http.StatusCreated)
 	if err != nil {
 		return nil, err
 	}
@@ -109,47 +92,26 @@ func createManagedConfig(ctx context.Context, client *simplemdm.Client, appID, k
 }
 
 func deleteManagedConfig(ctx context.Context, client *simplemdm.Client, appID, configID string) error {
-	url := fmt.Sprintf("https://%s/api/v1/apps/%s/managed_configs/%s", client.HostName, appID, configID)
+	url := simplemdmext.APIURL(client, "apps/%s/managed_configs/%s", appID, configID)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
 
-	_, err = client.RequestResponse204(req)
+	_, err = simplemdmext.Do(client, req, http.StatusNoContent)
 	return err
 }
 
 func pushManagedConfigUpdates(ctx context.Context, client *simplemdm.Client, appID string) error {
-	url := fmt.Sprintf("https://%s/api/v1/apps/%s/managed_configs/push", client.HostName, appID)
+	url := simplemdmext.APIURL(client, "apps/%s/managed_configs/push", appID)
 
-	type requester func(*http.Request) ([]byte, error)
-
-	attempts := []struct {
-		fn       requester
-		expected string
-	}{
-		{fn: client.RequestResponse202, expected: "202"},
-		{fn: client.RequestResponse200, expected: "200"},
-		{fn: client.RequestResponse204, expected: "204"},
-	}
-
-	for _, attempt := range attempts {
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
-		if err != nil {
-			return err
-		}
-
-		if _, err = attempt.fn(req); err != nil {
-			if strings.Contains(err.Error(), "non "+attempt.expected+" status code") {
-				continue
-			}
-
-			return err
-		}
-
-		return nil
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("failed to push managed config updates for app %s", appID)
+	// The push endpoint has been observed to answer with 202, 200 or 204.
+	_, err = simplemdmext.Do(client, req, http.StatusAccepted, http.StatusOK, http.StatusNoContent)
+	return err
 }
@@ -106,13 +106,17 @@ func (r *managedConfigResource) Configure(_ context.Context, req resource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if ok {
-		r.client = client
+		r.client = data.client
 	}
 }
 
 func (r *managedConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	var plan managedConfigModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -162,7 +166,7 @@ func (r *managedConfigResource) Read(ctx context.Context, req resource.ReadReque
 
 	config, err := fetchManagedConfig(ctx, r.client, appID, configID)
 	if err != nil {
-		if errors.Is(err, errManagedConfigNotFound) {
+		if errors.Is(err, errManagedConfigNotFound) || isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -185,6 +189,10 @@ func (r *managedConfigResource) Read(ctx context.Context, req resource.ReadReque
 }
 
 func (r *managedConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	resp.Diagnostics.AddError(
 		"Managed config update not supported",
 		"Managed app configurations must be replaced to change values.",
@@ -192,6 +200,10 @@ func (r *managedConfigResource) Update(ctx context.Context, req resource.UpdateR
 }
 
 func (r *managedConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state managedConfigModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -205,7 +217,7 @@ func (r *managedConfigResource) Delete(ctx context.Context, req resource.DeleteR
 		return
 	}
 
-	if err := deleteManagedConfig(ctx, r.client, appID, configID); err != nil {
+	if err := deleteManagedConfig(ctx, r.client, appID, configID); err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError(
 			"Error deleting managed app configuration",
 			err.Error(),
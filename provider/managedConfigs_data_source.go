@@ -2,11 +2,10 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -124,16 +123,16 @@ func (d *managedConfigsDataSource) Configure(_ context.Context, req datasource.C
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *simplemdm.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 // fetchAllManagedConfigs retrieves all managed configs for an app
@@ -142,22 +141,8 @@ func fetchAllManagedConfigs(ctx context.Context, client *simplemdm.Client, appID
 		return nil, fmt.Errorf("simplemdm client is not configured")
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/apps/%s/managed_configs", client.HostName, appID)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	body, err := client.RequestResponse200(req)
-	if err != nil {
-		return nil, err
-	}
-
-	var response managedConfigListResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, err
-	}
-
-	return response.Data, nil
+	path := fmt.Sprintf("apps/%s/managed_configs", appID)
+	return simplemdmext.ListAll(ctx, client, path, simplemdmext.ListOptions{}, simplemdmext.IntCursor(func(config managedConfigAPIResource) int {
+		return config.ID
+	}))
 }
@@ -2,23 +2,39 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
+	"time"
 
-	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ provider.Provider = &simplemdmProvider{}
+	_ provider.Provider            = &simplemdmProvider{}
+	_ provider.ProviderWithActions = &simplemdmProvider{}
 )
 
+// apiTransport, when set, performs the HTTP exchanges of every client the
+// provider configures. Acceptance tests set it to record or replay API
+// traffic.
+var apiTransport http.RoundTripper
+
 // New is a helper function to simplify provider server and testing implementation.
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
@@ -38,8 +54,24 @@ type simplemdmProvider struct {
 
 // simplemdmProviderModel maps provider schema data to a Go type.
 type simplemdmProviderModel struct {
-	Host   types.String `tfsdk:"host"`
-	APIKey types.String `tfsdk:"apikey"`
+	Host         types.String `tfsdk:"host"`
+	Endpoint     types.String `tfsdk:"endpoint"`
+	APIKey       types.String `tfsdk:"apikey"`
+	APIKeyFile   types.String `tfsdk:"apikey_file"`
+	APIKeyCmd    types.List   `tfsdk:"apikey_command"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMinWait types.Int64  `tfsdk:"retry_min_wait"`
+	RetryMaxWait types.Int64  `tfsdk:"retry_max_wait"`
+
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	Burst             types.Int64   `tfsdk:"burst"`
+
+	ExpectedAccountName types.String `tfsdk:"expected_account_name"`
+	ExpectedAccountID   types.String `tfsdk:"expected_account_id"`
+
+	ReadOnly types.Bool `tfsdk:"read_only"`
+
+	AllowedDestructiveCommands types.Set `tfsdk:"allowed_destructive_commands"`
 }
 
 // Metadata returns the provider type name.
@@ -55,10 +87,97 @@ func (p *simplemdmProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 			"host": schema.StringAttribute{
 				Optional:    true,
 				Description: "API host for you instance, can be set as environment variable SIMPLEMDM_HOST, if not set it will default to a.simplemdm.com",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("endpoint")),
+				},
+			},
+			"endpoint": schema.StringAttribute{
+				Optional:    true,
+				Description: "Base URL of the SimpleMDM API including scheme and an optional path prefix, for example http://localhost:8080 or https://proxy.example.com/simplemdm. API paths such as /api/v1/apps are appended to it. Can be set as environment variable SIMPLEMDM_ENDPOINT and takes precedence over host.",
 			},
 			"apikey": schema.StringAttribute{
 				Optional:    true,
 				Description: "API key for you instance, can be set as environment variable SIMPLEMDM_APIKEY",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("apikey_file"), path.MatchRoot("apikey_command")),
+				},
+			},
+			"apikey_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a file containing the API key, for example a mounted secret. Surrounding whitespace is ignored. Conflicts with apikey and apikey_command.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(path.MatchRoot("apikey_command")),
+				},
+			},
+			"apikey_command": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Credential helper that prints the API key to standard output, given as the executable followed by its arguments, for example [\"op\", \"read\", \"op://vault/simplemdm/credential\"]. The command is not run through a shell and must finish within 30 seconds. Conflicts with apikey and apikey_file.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of times a rate limited (429) or failed (5xx) request is retried, defaults to 4. Set to 0 to disable retries.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_min_wait": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum time in seconds to wait before retrying a request, defaults to 1. The wait doubles with every attempt unless the API sends a Retry-After header. Set to 0 to retry without waiting.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_wait": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum time in seconds to wait between retries, defaults to 30. Longer Retry-After headers are capped to this value.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional:    true,
+				Description: "Maximum sustained number of API requests per second shared by all resources and data sources, defaults to 10. Set to 0 to disable client-side rate limiting.",
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"burst": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of API requests that may be sent back to back before requests_per_second applies, defaults to 10.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, the provider only sends GET requests: any other request is rejected before it reaches the API and every resource create, update or delete fails with an error. Useful to run terraform plan with a production API key. Defaults to false.",
+			},
+			"allowed_destructive_commands": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Destructive device commands that simplemdm_device_command may send: clear_passcode, delete_user, unenroll and wipe. Commands not listed are rejected during plan. Defaults to none.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.OneOf(destructiveDeviceCommands()...)),
+				},
+			},
+			"expected_account_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the SimpleMDM account the API key must belong to. When set, the provider looks up the account during configuration and refuses to operate against any other account.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"expected_account_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "ID of the SimpleMDM account the API key must belong to. When set, the provider looks up the account during configuration and refuses to operate against any other account.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 		},
 	}
@@ -86,6 +205,15 @@ func (p *simplemdmProvider) Configure(ctx context.Context, req provider.Configur
 		)
 	}
 
+	if config.Endpoint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unknown SimpleMDM Endpoint",
+			"The provider cannot create the simplemdm API client as there is an unknown configuration value for the SimpleMDM endpoint. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the SIMPLEMDM_ENDPOINT environment variable.",
+		)
+	}
+
 	if config.APIKey.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("apikey"),
@@ -95,6 +223,24 @@ func (p *simplemdmProvider) Configure(ctx context.Context, req provider.Configur
 		)
 	}
 
+	if config.APIKeyFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("apikey_file"),
+			"Unknown SimpleMDM API key file",
+			"The provider cannot create the simplemdm API client as there is an unknown configuration value for the SimpleMDM API key file. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.APIKeyCmd.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("apikey_command"),
+			"Unknown SimpleMDM API key command",
+			"The provider cannot create the simplemdm API client as there is an unknown configuration value for the SimpleMDM API key command. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -102,16 +248,53 @@ func (p *simplemdmProvider) Configure(ctx context.Context, req provider.Configur
 	// Default values to environment variables, but override
 	// with Terraform configuration value if set.
 	host := os.Getenv("SIMPLEMDM_HOST")
+	endpoint := os.Getenv("SIMPLEMDM_ENDPOINT")
 	apikey := os.Getenv("SIMPLEMDM_APIKEY")
 
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
 	}
 
+	if !config.Endpoint.IsNull() {
+		endpoint = config.Endpoint.ValueString()
+	}
+
 	if !config.APIKey.IsNull() {
 		apikey = config.APIKey.ValueString()
 	}
 
+	if !config.APIKeyFile.IsNull() {
+		key, err := readAPIKeyFile(config.APIKeyFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("apikey_file"),
+				"Unable to read SimpleMDM API key file",
+				"The provider cannot create the SimpleMDM API client because the API key could not be read from apikey_file: "+err.Error(),
+			)
+			return
+		}
+		apikey = key
+	}
+
+	if !config.APIKeyCmd.IsNull() {
+		var command []string
+		resp.Diagnostics.Append(config.APIKeyCmd.ElementsAs(ctx, &command, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		key, err := runAPIKeyCommand(ctx, command)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("apikey_command"),
+				"Unable to run SimpleMDM API key command",
+				"The provider cannot create the SimpleMDM API client because apikey_command did not return an API key: "+err.Error(),
+			)
+			return
+		}
+		apikey = key
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 	if host == "" {
@@ -123,26 +306,132 @@ func (p *simplemdmProvider) Configure(ctx context.Context, req provider.Configur
 			path.Root("apikey"),
 			"Missing SimpleMDM API key",
 			"The provider cannot create the SimpleMDM API client as there is a missing or empty value for the SimpleMDM API key. "+
-				"Set the apikey value in the configuration or use the SIMPLEMDM_APIKEY environment variable. "+
+				"Set the apikey, apikey_file or apikey_command value in the configuration or use the SIMPLEMDM_APIKEY environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
+	clientOptions := simplemdmext.DefaultClientOptions()
+	clientOptions.Transport = apiTransport
+
+	if endpoint != "" {
+		parsed, err := simplemdmext.ParseEndpoint(endpoint)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Invalid SimpleMDM Endpoint",
+				"The provider cannot create the SimpleMDM API client because the endpoint is not a valid URL: "+err.Error(),
+			)
+		} else {
+			clientOptions.Endpoint = parsed
+			host = parsed.Host
+		}
+	}
+
+	if !config.MaxRetries.IsNull() {
+		clientOptions.MaxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	if !config.RetryMinWait.IsNull() {
+		clientOptions.RetryMinWait = time.Duration(config.RetryMinWait.ValueInt64()) * time.Second
+	}
+
+	if !config.RetryMaxWait.IsNull() {
+		clientOptions.RetryMaxWait = time.Duration(config.RetryMaxWait.ValueInt64()) * time.Second
+	}
+
+	if !config.RequestsPerSecond.IsNull() {
+		clientOptions.RequestsPerSecond = config.RequestsPerSecond.ValueFloat64()
+	}
+
+	if !config.Burst.IsNull() {
+		clientOptions.Burst = int(config.Burst.ValueInt64())
+	}
+
+	if !config.ReadOnly.IsNull() {
+		clientOptions.ReadOnly = config.ReadOnly.ValueBool()
+	}
+
+	if clientOptions.RetryMaxWait < clientOptions.RetryMinWait {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_max_wait"),
+			"Invalid SimpleMDM retry configuration",
+			"The retry_max_wait value must be greater than or equal to retry_min_wait.",
+		)
+	}
+
+	settings := providerSettings{allowedDestructiveCommands: map[string]struct{}{}}
+	if config.AllowedDestructiveCommands.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("allowed_destructive_commands"),
+			"Unknown allowed destructive commands",
+			"The provider cannot decide which destructive device commands are allowed as there is an unknown configuration value for allowed_destructive_commands. "+
+				"Set the value statically in the configuration.",
+		)
+	} else if !config.AllowedDestructiveCommands.IsNull() {
+		var commands []string
+		resp.Diagnostics.Append(config.AllowedDestructiveCommands.ElementsAs(ctx, &commands, false)...)
+		for _, command := range commands {
+			settings.allowedDestructiveCommands[command] = struct{}{}
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Never let the API key reach TF_LOG output, whether logged as a field or
+	// embedded in a message such as an error string.
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "simplemdm_apikey")
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, apikey)
+	ctx = tflog.MaskMessageStrings(ctx, apikey)
+
 	ctx = tflog.SetField(ctx, "simplemdm_host", host)
+	ctx = tflog.SetField(ctx, "simplemdm_endpoint", endpoint)
 	ctx = tflog.SetField(ctx, "simplemdm_apikey", apikey)
+	ctx = tflog.SetField(ctx, "simplemdm_read_only", clientOptions.ReadOnly)
 
 	tflog.Debug(ctx, "Creating SimpleMDM client")
 
-	apiClient := simplemdm.NewClient(host, apikey)
+	clientOptions.LogContext = ctx
+
+	apiClient, err := simplemdmext.NewClient(host, apikey, clientOptions)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create SimpleMDM API Client",
+			"An unexpected error occurred when creating the SimpleMDM API client: "+err.Error(),
+		)
+		return
+	}
+
+	if !config.ExpectedAccountName.IsNull() || !config.ExpectedAccountID.IsNull() {
+		account, err := simplemdmext.GetAccount(ctx, apiClient)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Verify SimpleMDM Account",
+				"The provider could not look up the account of the API key to compare it with expected_account_name or expected_account_id: "+err.Error(),
+			)
+			return
+		}
+
+		if err := verifyAccount(account.Data, config.ExpectedAccountName.ValueString(), config.ExpectedAccountID.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"SimpleMDM Account Mismatch",
+				"The provider refuses to operate because the API key belongs to a different account than configured: "+err.Error()+". "+
+					"Check that the provider block or provider alias uses the API key of the intended account.",
+			)
+			return
+		}
+
+		tflog.Info(ctx, "Verified SimpleMDM account", map[string]any{"simplemdm_account": account.Data.Attributes.Name})
+	}
 
-	// Make the SimpleMDM client available during DataSource and Resource
-	// type Configure methods.
-	resp.DataSourceData = apiClient
-	resp.ResourceData = apiClient
+	// Make the SimpleMDM client and settings available during DataSource,
+	// Resource and Action type Configure methods.
+	data := &providerData{client: apiClient, settings: settings}
+	resp.DataSourceData = data
+	resp.ResourceData = data
+	resp.ActionData = data
 
 	tflog.Info(ctx, "Configured SimpleMDM client", map[string]any{"success": true})
 
@@ -157,3 +446,28 @@ func (p *simplemdmProvider) DataSources(_ context.Context) []func() datasource.D
 func (p *simplemdmProvider) Resources(_ context.Context) []func() resource.Resource {
 	return ResourceFactories()
 }
+
+// Actions defines the actions implemented in the provider.
+func (p *simplemdmProvider) Actions(_ context.Context) []func() action.Action {
+	return ActionFactories()
+}
+
+// verifyAccount compares the account of the API key with the expected name
+// and ID. Empty expectations are not checked.
+func verifyAccount(account simplemdmext.AccountData, expectedName, expectedID string) error {
+	if expectedName != "" && account.Attributes.Name != expectedName {
+		return fmt.Errorf("expected account name %q, got %q", expectedName, account.Attributes.Name)
+	}
+
+	if expectedID != "" {
+		id := account.AccountID()
+		if id == "" {
+			return fmt.Errorf("expected account ID %q, but the API did not report an account ID", expectedID)
+		}
+		if id != expectedID {
+			return fmt.Errorf("expected account ID %q, got %q", expectedID, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+// configureTestProvider runs the provider Configure method against the
+// configuration built by providerTestConfig.
+func configureTestProvider(ctx context.Context, t *testing.T, values map[string]tftypes.Value) *provider.ConfigureResponse {
+	t.Helper()
+
+	p := New("test")()
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: providerTestConfig(ctx, t, p, values)}, resp)
+
+	return resp
+}
+
+// providerTestConfig builds a provider configuration containing the given
+// attribute values; every other attribute is null.
+func providerTestConfig(ctx context.Context, t *testing.T, p provider.Provider, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+
+	schemaResp := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatalf("provider schema is not an object")
+	}
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+			continue
+		}
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	return tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(objectType, attributes),
+	}
+}
+
+func TestProviderConfigureDoesNotLogAPIKey(t *testing.T) {
+	t.Setenv("SIMPLEMDM_APIKEY", "")
+	t.Setenv("SIMPLEMDM_HOST", "")
+	t.Setenv("SIMPLEMDM_ENDPOINT", "")
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	resp := configureTestProvider(ctx, t, map[string]tftypes.Value{
+		"apikey": tftypes.NewValue(tftypes.String, "super-secret-api-key"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if output.Len() == 0 {
+		t.Fatalf("expected Configure to emit logs")
+	}
+
+	if strings.Contains(output.String(), "super-secret-api-key") {
+		t.Fatalf("API key leaked into provider logs: %s", output.String())
+	}
+}
+
+func TestProviderConfigureRejectsInvalidEndpoint(t *testing.T) {
+	t.Setenv("SIMPLEMDM_ENDPOINT", "")
+
+	resp := configureTestProvider(context.Background(), t, map[string]tftypes.Value{
+		"apikey":   tftypes.NewValue(tftypes.String, "key"),
+		"endpoint": tftypes.NewValue(tftypes.String, "localhost:8080"),
+	})
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error for an endpoint without scheme")
+	}
+}
+
+func TestProviderConfigureReadsAPIKeyFile(t *testing.T) {
+	t.Setenv("SIMPLEMDM_APIKEY", "env-key")
+	t.Setenv("SIMPLEMDM_ENDPOINT", "")
+
+	keyFile := filepath.Join(t.TempDir(), "apikey")
+	if err := os.WriteFile(keyFile, []byte("file-key\n"), 0o600); err != nil {
+		t.Fatalf("unable to write key file: %v", err)
+	}
+
+	resp := configureTestProvider(context.Background(), t, map[string]tftypes.Value{
+		"apikey_file": tftypes.NewValue(tftypes.String, keyFile),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	data, ok := resp.ResourceData.(*providerData)
+	if !ok || data.client.APIKey != "file-key" {
+		t.Fatalf("expected the client to use the key from apikey_file, got %#v", resp.ResourceData)
+	}
+}
+
+func TestProviderConfigureRunsAPIKeyCommand(t *testing.T) {
+	t.Setenv("SIMPLEMDM_APIKEY", "")
+	t.Setenv("SIMPLEMDM_ENDPOINT", "")
+
+	command := func(args ...string) tftypes.Value {
+		values := make([]tftypes.Value, 0, len(args))
+		for _, arg := range args {
+			values = append(values, tftypes.NewValue(tftypes.String, arg))
+		}
+		return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, values)
+	}
+
+	resp := configureTestProvider(context.Background(), t, map[string]tftypes.Value{
+		"apikey_command": command("echo", "command-key"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	data, ok := resp.ResourceData.(*providerData)
+	if !ok || data.client.APIKey != "command-key" {
+		t.Fatalf("expected the client to use the key from apikey_command, got %#v", resp.ResourceData)
+	}
+
+	resp = configureTestProvider(context.Background(), t, map[string]tftypes.Value{
+		"apikey_command": command("false"),
+	})
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("expected an error for a failing apikey_command")
+	}
+}
+
+func TestProviderSchemaRejectsMultipleAPIKeySources(t *testing.T) {
+	ctx := context.Background()
+
+	commandValue := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "echo"),
+	})
+
+	tests := map[string]map[string]tftypes.Value{
+		"apikey and apikey_file": {
+			"apikey":      tftypes.NewValue(tftypes.String, "key"),
+			"apikey_file": tftypes.NewValue(tftypes.String, "/nonexistent"),
+		},
+		"apikey and apikey_command": {
+			"apikey":         tftypes.NewValue(tftypes.String, "key"),
+			"apikey_command": commandValue,
+		},
+		"apikey_file and apikey_command": {
+			"apikey_file":    tftypes.NewValue(tftypes.String, "/nonexistent"),
+			"apikey_command": commandValue,
+		},
+	}
+
+	for name, values := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := providerTestConfig(ctx, t, New("test")(), values)
+
+			var diags diag.Diagnostics
+			for _, attribute := range []string{"apikey", "apikey_file"} {
+				attributePath := path.Root(attribute)
+				var value types.String
+				diags.Append(config.GetAttribute(ctx, attributePath, &value)...)
+
+				stringAttribute := config.Schema.GetAttributes()[attribute].(schema.StringAttribute)
+				for _, v := range stringAttribute.Validators {
+					validateResp := &validator.StringResponse{}
+					v.ValidateString(ctx, validator.StringRequest{
+						Path:           attributePath,
+						PathExpression: attributePath.Expression(),
+						ConfigValue:    value,
+						Config:         config,
+					}, validateResp)
+					diags.Append(validateResp.Diagnostics...)
+				}
+			}
+
+			if !diags.HasError() {
+				t.Fatalf("expected the schema to reject %s", name)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureVerifiesAccount(t *testing.T) {
+	t.Setenv("SIMPLEMDM_APIKEY", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/account" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"type":"account","id":42,"attributes":{"name":"Staging"}}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		values  map[string]tftypes.Value
+		wantErr bool
+	}{
+		{
+			name:   "matching name and id",
+			values: map[string]tftypes.Value{"expected_account_name": tftypes.NewValue(tftypes.String, "Staging"), "expected_account_id": tftypes.NewValue(tftypes.String, "42")},
+		},
+		{
+			name:    "different name",
+			values:  map[string]tftypes.Value{"expected_account_name": tftypes.NewValue(tftypes.String, "Production")},
+			wantErr: true,
+		},
+		{
+			name:    "different id",
+			values:  map[string]tftypes.Value{"expected_account_id": tftypes.NewValue(tftypes.String, "7")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.values["apikey"] = tftypes.NewValue(tftypes.String, "key")
+			tt.values["endpoint"] = tftypes.NewValue(tftypes.String, server.URL)
+
+			resp := configureTestProvider(context.Background(), t, tt.values)
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if tt.wantErr && resp.ResourceData != nil {
+				t.Fatalf("the client must not be exposed when the account does not match")
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"github.com/DavidKrau/simplemdm-go-client"
+)
+
+// providerData is what Configure hands to data sources, resources and
+// actions: the API client and the provider configuration they need besides
+// it. Every provider alias has its own.
+type providerData struct {
+	client   *simplemdm.Client
+	settings providerSettings
+}
+
+// providerSettings holds provider configuration that resources need besides
+// the API client.
+type providerSettings struct {
+	// allowedDestructiveCommands lists the destructive device commands the
+	// provider configuration permits.
+	allowedDestructiveCommands map[string]struct{}
+}
+
+func (s providerSettings) destructiveCommandAllowed(command string) bool {
+	_, ok := s.allowedDestructiveCommands[command]
+	return ok
+}
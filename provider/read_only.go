@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// rejectReadOnly reports an error and returns true when the provider runs in
+// read_only mode. Create, Update and Delete call it before doing anything else
+// so no request is attempted.
+func rejectReadOnly(client *simplemdm.Client, diags *diag.Diagnostics, operation string) bool {
+	return rejectReadOnlyTarget(client, diags, operation+" this resource")
+}
+
+// rejectReadOnlyAction is rejectReadOnly for the Invoke method of actions.
+func rejectReadOnlyAction(client *simplemdm.Client, diags *diag.Diagnostics) bool {
+	return rejectReadOnlyTarget(client, diags, "invoke this action")
+}
+
+func rejectReadOnlyTarget(client *simplemdm.Client, diags *diag.Diagnostics, target string) bool {
+	if !simplemdmext.IsReadOnly(client) {
+		return false
+	}
+
+	diags.AddError(
+		"SimpleMDM provider is read-only",
+		"The provider is configured with read_only = true, so it cannot "+target+". "+
+			"Remove read_only from the provider configuration to apply changes.",
+	)
+
+	return true
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func TestReadOnlyResourcesRejectMutations(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	endpoint, err := simplemdmext.ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	options := simplemdmext.DefaultClientOptions()
+	options.Endpoint = endpoint
+	options.ReadOnly = true
+	client, err := simplemdmext.NewClient("", "key", options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, definition := range ResourceDefinitions() {
+		r := definition.Factory()
+
+		configurable, ok := r.(resource.ResourceWithConfigure)
+		if !ok {
+			t.Fatalf("%s does not accept the provider client", definition.TypeName)
+		}
+		configureResp := &resource.ConfigureResponse{}
+		configurable.Configure(ctx, resource.ConfigureRequest{ProviderData: &providerData{client: client}}, configureResp)
+		if configureResp.Diagnostics.HasError() {
+			t.Fatalf("%s: unexpected configure diagnostics: %v", definition.TypeName, configureResp.Diagnostics)
+		}
+
+		createResp := &resource.CreateResponse{}
+		r.Create(ctx, resource.CreateRequest{}, createResp)
+
+		updateResp := &resource.UpdateResponse{}
+		r.Update(ctx, resource.UpdateRequest{}, updateResp)
+
+		deleteResp := &resource.DeleteResponse{}
+		r.Delete(ctx, resource.DeleteRequest{}, deleteResp)
+
+		for operation, errors := range map[string]int{
+			"Create": createResp.Diagnostics.ErrorsCount(),
+			"Update": updateResp.Diagnostics.ErrorsCount(),
+			"Delete": deleteResp.Diagnostics.ErrorsCount(),
+		} {
+			if errors != 1 {
+				t.Errorf("%s %s: expected exactly the read-only error, got %d errors", definition.TypeName, operation, errors)
+			}
+		}
+		if summary := createResp.Diagnostics.Errors()[0].Summary(); summary != "SimpleMDM provider is read-only" {
+			t.Errorf("%s Create: unexpected diagnostic %q", definition.TypeName, summary)
+		}
+	}
+
+	if requests != 0 {
+		t.Fatalf("read-only resources sent %d requests", requests)
+	}
+}
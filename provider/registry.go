@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 )
@@ -24,6 +25,15 @@ type DataSourceDefinition struct {
 	APIEndpoints []string
 }
 
+type ActionDefinition struct {
+	TypeName     string
+	Factory      func() action.Action
+	DocsPath     string
+	ExampleDirs  []string
+	TestFiles    []string
+	APIEndpoints []string
+}
+
 var resourceDefinitions = []ResourceDefinition{
 	{
 		TypeName:     "simplemdm_app",
@@ -49,13 +59,33 @@ var resourceDefinitions = []ResourceDefinition{
 		TestFiles:    []string{"provider/assignmentGroup_resource_test.go"},
 		APIEndpoints: []string{"/api/v1/assignment_groups"},
 	},
+	{
+		TypeName:     "simplemdm_assignmentgroup_app",
+		Factory:      AssignmentGroupAppResource,
+		DocsPath:     "docs/resources/assignmentgroup_app.md",
+		ExampleDirs:  []string{"examples/resources/simplemdm_assignmentgroup_app"},
+		TestFiles:    []string{"provider/assignmentGroup_app_resource_test.go"},
+		APIEndpoints: []string{"/api/v1/assignment_groups/{ASSIGNMENT_GROUP_ID}/apps/{APP_ID}"},
+	},
+	{
+		TypeName:    "simplemdm_bulk_device_command",
+		Factory:     BulkDeviceCommandResource,
+		DocsPath:    "docs/resources/bulk_device_command.md",
+		ExampleDirs: []string{"examples/resources/simplemdm_bulk_device_command"},
+		TestFiles:   []string{"provider/bulk_device_command_resource_test.go"},
+		APIEndpoints: []string{
+			"/api/v1/devices",
+			"/api/v1/assignment_groups/{ASSIGNMENT_GROUP_ID}",
+			"/api/v1/devices/{DEVICE_ID}/{COMMAND}",
+		},
+	},
 	{
 		TypeName:     "simplemdm_customprofile",
 		Factory:      CustomProfileResource,
 		DocsPath:     "docs/resources/customprofile.md",
 		ExampleDirs:  []string{"examples/resources/simplemdm_customprofile"},
 		TestFiles:    []string{"provider/customProfile_resource_test.go"},
-		APIEndpoints: []string{"/api/v1/custom_profiles"},
+		APIEndpoints: []string{"/api/v1/custom_configuration_profiles"},
 	},
 	{
 		TypeName:     "simplemdm_profile",
@@ -121,6 +151,18 @@ var resourceDefinitions = []ResourceDefinition{
 		},
 		TestsOptional: true,
 	},
+	{
+		TypeName:    "simplemdm_device_lost_mode",
+		Factory:     DeviceLostModeResource,
+		DocsPath:    "docs/resources/device_lost_mode.md",
+		ExampleDirs: []string{"examples/resources/simplemdm_device_lost_mode"},
+		TestFiles:   []string{"provider/device_lost_mode_resource_test.go"},
+		APIEndpoints: []string{
+			"/api/v1/devices/{DEVICE_ID}/lost_mode",
+			"/api/v1/devices/{DEVICE_ID}/lost_mode/play_sound",
+			"/api/v1/devices/{DEVICE_ID}/lost_mode/update_location",
+		},
+	},
 	{
 		TypeName:     "simplemdm_devicegroup",
 		Factory:      DeviceGroupResource,
@@ -169,6 +211,14 @@ var resourceDefinitions = []ResourceDefinition{
 }
 
 var dataSourceDefinitions = []DataSourceDefinition{
+	{
+		TypeName:     "simplemdm_account",
+		Factory:      AccountDataSource,
+		DocsPath:     "docs/data-sources/account.md",
+		ExampleDirs:  []string{"examples/data-sources/simplemdm_account"},
+		TestFiles:    []string{"provider/account_data_source_test.go"},
+		APIEndpoints: []string{"/api/v1/account"},
+	},
 	{
 		TypeName:     "simplemdm_app",
 		Factory:      AppDataSource,
@@ -199,7 +249,7 @@ var dataSourceDefinitions = []DataSourceDefinition{
 		DocsPath:     "docs/data-sources/customprofile.md",
 		ExampleDirs:  []string{"examples/data-sources/simplemdm_customprofile"},
 		TestFiles:    []string{"provider/customProfile_data_source_test.go"},
-		APIEndpoints: []string{"/api/v1/custom_profiles"},
+		APIEndpoints: []string{"/api/v1/custom_configuration_profiles"},
 	},
 	{
 		TypeName:     "simplemdm_customdeclaration",
@@ -335,7 +385,7 @@ var dataSourceDefinitions = []DataSourceDefinition{
 		DocsPath:     "docs/data-sources/customprofiles.md",
 		ExampleDirs:  []string{"examples/data-sources/simplemdm_customprofiles"},
 		TestFiles:    []string{"provider/customProfiles_data_source_test.go"},
-		APIEndpoints: []string{"/api/v1/custom_profiles"},
+		APIEndpoints: []string{"/api/v1/custom_configuration_profiles"},
 	},
 	{
 		TypeName:     "simplemdm_customdeclarations",
@@ -387,6 +437,17 @@ var dataSourceDefinitions = []DataSourceDefinition{
 	},
 }
 
+var actionDefinitions = []ActionDefinition{
+	{
+		TypeName:     "simplemdm_device_command",
+		Factory:      DeviceCommandAction,
+		DocsPath:     "docs/actions/device_command.md",
+		ExampleDirs:  []string{"examples/actions/simplemdm_device_command"},
+		TestFiles:    []string{"provider/device_command_action_test.go"},
+		APIEndpoints: []string{"/api/v1/devices/{DEVICE_ID}/{COMMAND}"},
+	},
+}
+
 func ResourceFactories() []func() resource.Resource {
 	factories := make([]func() resource.Resource, 0, len(resourceDefinitions))
 	for _, definition := range resourceDefinitions {
@@ -430,3 +491,16 @@ func ResourceDefinitions() []ResourceDefinition {
 func DataSourceDefinitions() []DataSourceDefinition {
 	return append([]DataSourceDefinition(nil), dataSourceDefinitions...)
 }
+
+func ActionFactories() []func() action.Action {
+	factories := make([]func() action.Action, 0, len(actionDefinitions))
+	for _, definition := range actionDefinitions {
+		factories = append(factories, definition.Factory)
+	}
+
+	return factories
+}
+
+func ActionDefinitions() []ActionDefinition {
+	return append([]ActionDefinition(nil), actionDefinitions...)
+}
@@ -19,8 +19,9 @@ import (
 )
 
 var (
-	_ resource.Resource              = &scriptJobResource{}
-	_ resource.ResourceWithConfigure = &scriptJobResource{}
+	_ resource.Resource                = &scriptJobResource{}
+	_ resource.ResourceWithConfigure   = &scriptJobResource{}
+	_ resource.ResourceWithImportState = &scriptJobResource{}
 )
 
 // scriptJobsResourceModel maps the resource schema data.
@@ -63,7 +64,12 @@ func (r *scriptJobResource) Configure(_ context.Context, req resource.ConfigureR
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
+}
+
+// ImportState imports a script job by its ID.
+func (r *scriptJobResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
 // Metadata returns the resource type name.
@@ -227,6 +233,10 @@ func (r *scriptJobResource) Schema(_ context.Context, _ resource.SchemaRequest,
 
 // Create a new resource
 func (r *scriptJobResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	var plan scriptJobResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -325,6 +335,10 @@ func (r *scriptJobResource) Create(ctx context.Context, req resource.CreateReque
 }
 
 func (r *scriptJobResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state scriptJobResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -382,6 +396,10 @@ func (r *scriptJobResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *scriptJobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	// Force the recreation by seeing an appropriate error
 	resp.Diagnostics.AddError(
 		"Update Not Supported",
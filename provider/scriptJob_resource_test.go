@@ -69,6 +69,9 @@ func TestAccScriptJobResource(t *testing.T) {
 				ResourceName:      "simplemdm_scriptjob.test_job",
 				ImportState:       true,
 				ImportStateVerify: true,
+				// Jobs report per-device results only, not the script and
+				// targets they were started with.
+				ImportStateVerifyIgnore: []string{"script_id", "device_ids", "group_ids", "assignment_group_ids"},
 			},
 			// Update and Read testing
 			{
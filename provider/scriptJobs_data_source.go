@@ -140,7 +140,7 @@ func (d *scriptJobsDataSource) Configure(_ context.Context, req datasource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -150,10 +150,10 @@ func (d *scriptJobsDataSource) Configure(_ context.Context, req datasource.Confi
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 // fetchAllScriptJobs retrieves all script jobs with pagination support
 func fetchAllScriptJobs(ctx context.Context, client *simplemdm.Client) ([]scriptJobResponse, error) {
-	return listScriptJobs(ctx, client, 0)
+	return listScriptJobs(ctx, client)
 }
@@ -3,12 +3,11 @@ package provider
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
 
 	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -84,12 +83,12 @@ type scriptJobDetailsResponse struct {
 }
 
 func fetchScriptJobDetails(ctx context.Context, client *simplemdm.Client, id string) (*scriptJobDetailsData, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/api/v1/script_jobs/%s", client.HostName, id), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, simplemdmext.APIURL(client, "script_jobs/%s", id), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := client.RequestResponse200(req)
+	body, err := simplemdmext.Do(client, req, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -179,10 +178,6 @@ func scriptJobDevicesListValue(ctx context.Context, devices []scriptJobDeviceDet
 	return list, diags
 }
 
-func isNotFoundError(err error) bool {
-	return err != nil && strings.Contains(err.Error(), "404")
-}
-
 type scriptJobResponse struct {
 	Data scriptJobData `json:"data"`
 }
@@ -222,49 +217,15 @@ type scriptJobFlat struct {
 	UpdatedAt           string
 }
 
-type scriptJobsListResponse struct {
-	Data    []scriptJobData `json:"data"`
-	HasMore bool            `json:"has_more"`
-}
-
-func listScriptJobs(ctx context.Context, client *simplemdm.Client, startingAfter int) ([]scriptJobResponse, error) {
+func listScriptJobs(ctx context.Context, client *simplemdm.Client) ([]scriptJobResponse, error) {
 	var allJobs []scriptJobResponse
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/script_jobs?limit=%d", client.HostName, limit)
-		if startingAfter > 0 {
-			url += fmt.Sprintf("&starting_after=%d", startingAfter)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
 
-		body, err := client.RequestResponse200(req)
+	cursor := simplemdmext.IntCursor(func(data scriptJobData) int { return data.ID })
+	for data, err := range simplemdmext.Paginate(ctx, client, "script_jobs", simplemdmext.ListOptions{Prefetch: true}, cursor) {
 		if err != nil {
 			return nil, err
 		}
-
-		var response scriptJobsListResponse
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, err
-		}
-
-		for _, data := range response.Data {
-			allJobs = append(allJobs, scriptJobResponse{Data: data})
-		}
-
-		if !response.HasMore {
-			break
-		}
-
-		if len(response.Data) > 0 {
-			startingAfter = response.Data[len(response.Data)-1].ID
-		} else {
-			break
-		}
+		allJobs = append(allJobs, scriptJobResponse{Data: data})
 	}
 
 	return allJobs, nil
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"strconv"
-	"strings"
 
 	"github.com/DavidKrau/simplemdm-go-client"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -49,7 +48,7 @@ func (r *scriptResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	r.client = req.ProviderData.(*simplemdm.Client)
+	r.client = req.ProviderData.(*providerData).client
 }
 
 // Metadata returns the resource type name.
@@ -114,6 +113,10 @@ func (r *scriptResource) ImportState(ctx context.Context, req resource.ImportSta
 
 // Create a new resource
 func (r *scriptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "create") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan scriptResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -160,7 +163,7 @@ func (r *scriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 	// Get script values from SimpleMDM
 	script, err := r.client.ScriptGet(state.ID.ValueString())
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 			return
 		}
@@ -188,6 +191,10 @@ func (r *scriptResource) Read(ctx context.Context, req resource.ReadRequest, res
 }
 
 func (r *scriptResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "update") {
+		return
+	}
+
 	//Retrieve values from plan
 	var plan scriptResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -220,6 +227,10 @@ func (r *scriptResource) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *scriptResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if rejectReadOnly(r.client, &resp.Diagnostics, "delete") {
+		return
+	}
+
 	var state scriptResourceModel
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -229,7 +240,7 @@ func (r *scriptResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	// Delete existing script
 	err := r.client.ScriptDelete(state.ID.ValueString())
-	if err != nil {
+	if err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError(
 			"Error Deleting SimpleMDM script",
 			"Could not delete script, unexpected error: "+err.Error(),
@@ -2,12 +2,11 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strconv"
 
 	"github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -124,7 +123,7 @@ func (d *scriptsDataSource) Configure(_ context.Context, req datasource.Configur
 		return
 	}
 
-	client, ok := req.ProviderData.(*simplemdm.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
@@ -134,56 +133,14 @@ func (d *scriptsDataSource) Configure(_ context.Context, req datasource.Configur
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 // fetchAllScripts retrieves all scripts with pagination support
 func fetchAllScripts(ctx context.Context, client *simplemdm.Client) ([]scriptDataList, error) {
-	var allScripts []scriptDataList
-	startingAfter := 0
-	limit := 100
-
-	for {
-		url := fmt.Sprintf("https://%s/api/v1/scripts?limit=%d", client.HostName, limit)
-		if startingAfter > 0 {
-			url += fmt.Sprintf("&starting_after=%d", startingAfter)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		body, err := client.RequestResponse200(req)
-		if err != nil {
-			return nil, err
-		}
-
-		var response scriptsAPIResponse
-		if err := json.Unmarshal(body, &response); err != nil {
-			return nil, err
-		}
-
-		allScripts = append(allScripts, response.Data...)
-
-		if !response.HasMore {
-			break
-		}
-
-		if len(response.Data) > 0 {
-			startingAfter = response.Data[len(response.Data)-1].ID
-		} else {
-			break
-		}
-	}
-
-	return allScripts, nil
-}
-
-// scriptsAPIResponse represents the paginated API response
-type scriptsAPIResponse struct {
-	Data    []scriptDataList `json:"data"`
-	HasMore bool             `json:"has_more"`
+	return simplemdmext.ListAll(ctx, client, "scripts", simplemdmext.ListOptions{}, simplemdmext.IntCursor(func(script scriptDataList) int {
+		return script.ID
+	}))
 }
 
 // scriptDataList represents a single script in the list response
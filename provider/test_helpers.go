@@ -1,23 +1,187 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	simplemdm "github.com/DavidKrau/simplemdm-go-client"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmext"
+	"github.com/DavidKrau/terraform-provider-simplemdm/internal/simplemdmfake"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
-// testAccPreCheck ensures acceptance tests run only when TF_ACC is enabled
-// and the required authentication information is present.
+const (
+	// testAccRecordEnv selects the cassette mode of acceptance tests:
+	// "record" saves the API traffic of every test, "replay" answers it from
+	// the saved cassettes without network access.
+	testAccRecordEnv = "SIMPLEMDM_RECORD"
+	// testAccCassetteDir holds one cassette per acceptance test.
+	testAccCassetteDir = "testfiles/cassettes"
+	// testAccCassetteFakeKey marks cassettes recorded against the fake API.
+	testAccCassetteFakeKey = "fake_api"
+)
+
+// testAccUnrecordedEnv lists the variables that are not saved with a
+// cassette because they hold secrets or only matter when recording.
+var testAccUnrecordedEnv = map[string]bool{
+	"SIMPLEMDM_APIKEY":   true,
+	"SIMPLEMDM_ENDPOINT": true,
+	"SIMPLEMDM_HOST":     true,
+	testAccRecordEnv:     true,
+}
+
+// testAccPreCheck ensures acceptance tests run only when TF_ACC is enabled.
+// Tests run against the SimpleMDM account of SIMPLEMDM_APIKEY when it is set
+// and against an in-memory fake of the API otherwise. SIMPLEMDM_RECORD
+// records their traffic to a cassette or replays it from one.
 func testAccPreCheck(t *testing.T) {
 	if os.Getenv("TF_ACC") == "" {
 		t.Skip("Acceptance tests require TF_ACC to be set")
 	}
 
+	mode := os.Getenv(testAccRecordEnv)
+	switch mode {
+	case "replay":
+		testAccReplayCassette(t)
+		return
+	case "", "record":
+	default:
+		t.Fatalf("%s must be record or replay, got %q", testAccRecordEnv, mode)
+	}
+
 	if os.Getenv("SIMPLEMDM_APIKEY") == "" {
-		t.Skip("Acceptance tests require SIMPLEMDM_APIKEY to be set")
+		testAccUseFakeServer(t)
+	}
+
+	if mode == "record" {
+		testAccRecordCassette(t)
+	}
+}
+
+// testAccConsistentAPI is set while the running acceptance test talks to the
+// fake API or replays traffic recorded from it.
+var testAccConsistentAPI bool
+
+// testAccEventuallyConsistent reports whether the running acceptance test
+// talks to the real API, which can answer reads with stale relationships
+// right after a write. The fake is always consistent.
+func testAccEventuallyConsistent() bool {
+	return !testAccConsistentAPI
+}
+
+// testAccSkipUnlessFakeAPI skips tests that need objects the real API would
+// reject, such as app binaries that are not genuine packages.
+func testAccSkipUnlessFakeAPI(t *testing.T, reason string) {
+	if !testAccConsistentAPI {
+		t.Skipf("Acceptance test only runs against the fake API: %s", reason)
+	}
+}
+
+// testAccCassettePath returns the cassette file of the running test.
+func testAccCassettePath(t *testing.T) string {
+	return filepath.Join(testAccCassetteDir, strings.ReplaceAll(t.Name(), "/", "_")+".json")
+}
+
+// testAccRecordCassette records the API traffic of the running test together
+// with its SIMPLEMDM_* fixture variables. The cassette is only saved when the
+// test passes.
+func testAccRecordCassette(t *testing.T) {
+	cassette := simplemdmext.NewCassette(testAccCassettePath(t), nil, os.Getenv("SIMPLEMDM_APIKEY"))
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(name, "SIMPLEMDM_") && !testAccUnrecordedEnv[name] {
+			cassette.Env[name] = value
+		}
+	}
+	if testAccConsistentAPI {
+		cassette.Env[testAccCassetteFakeKey] = "true"
+	}
+
+	apiTransport = cassette
+	t.Cleanup(func() {
+		apiTransport = nil
+		if t.Failed() || t.Skipped() {
+			return
+		}
+		if err := cassette.Save(); err != nil {
+			t.Errorf("saving cassette: %v", err)
+		}
+	})
+}
+
+// testAccReplayCassette answers the API requests of the running test from
+// its cassette and restores the fixture variables it was recorded with. The
+// test is skipped when no cassette was recorded.
+func testAccReplayCassette(t *testing.T) {
+	path := testAccCassettePath(t)
+	cassette, err := simplemdmext.LoadCassette(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skipf("No cassette recorded at %s", path)
+	}
+	if err != nil {
+		t.Fatalf("loading cassette: %v", err)
+	}
+
+	t.Setenv("SIMPLEMDM_APIKEY", "replay")
+	t.Setenv("SIMPLEMDM_ENDPOINT", "")
+	t.Setenv("SIMPLEMDM_HOST", "")
+	for name, value := range cassette.Env {
+		if strings.HasPrefix(name, "SIMPLEMDM_") {
+			t.Setenv(name, value)
+		}
+	}
+
+	testAccConsistentAPI = cassette.Env[testAccCassetteFakeKey] == "true"
+	apiTransport = cassette
+	t.Cleanup(func() {
+		testAccConsistentAPI = false
+		apiTransport = nil
+	})
+}
+
+// testAccUseFakeServer starts a fake SimpleMDM API for the current test,
+// seeds it with fixtures and points the provider and the fixture environment
+// variables at it.
+func testAccUseFakeServer(t *testing.T) {
+	server := simplemdmfake.NewServer()
+	testAccConsistentAPI = true
+	t.Cleanup(func() {
+		server.Close()
+		testAccConsistentAPI = false
+	})
+
+	fixtures := server.SeedFixtures()
+	env := map[string]string{
+		"SIMPLEMDM_APIKEY":                                 server.APIKey,
+		"SIMPLEMDM_ENDPOINT":                               server.URL,
+		"SIMPLEMDM_APP_ID":                                 fixtures.AppID,
+		"SIMPLEMDM_ASSIGNMENT_GROUP_ID":                    fixtures.AssignmentGroupID,
+		"SIMPLEMDM_ATTRIBUTE_NAME":                         fixtures.AttributeName,
+		"SIMPLEMDM_CUSTOM_DECLARATION_DEVICE_ID":           fixtures.DeviceID,
+		"SIMPLEMDM_DEVICE_GROUP_ID":                        fixtures.DeviceGroupID,
+		"SIMPLEMDM_DEVICE_GROUP_CLONE_SOURCE_ID":           fixtures.CloneSourceGroupID,
+		"SIMPLEMDM_DEVICE_GROUP_NAME":                      "Acceptance Device Group",
+		"SIMPLEMDM_DEVICE_GROUP_ATTRIBUTE_KEY":             fixtures.AttributeName,
+		"SIMPLEMDM_DEVICE_GROUP_ATTRIBUTE_VALUE":           "initial",
+		"SIMPLEMDM_DEVICE_GROUP_ATTRIBUTE_UPDATED_VALUE":   "updated",
+		"SIMPLEMDM_DEVICE_GROUP_PROFILE_ID":                fixtures.ProfileID,
+		"SIMPLEMDM_DEVICE_GROUP_PROFILE_UPDATED_ID":        fixtures.UpdatedProfileID,
+		"SIMPLEMDM_DEVICE_GROUP_CUSTOM_PROFILE_ID":         fixtures.CustomProfileID,
+		"SIMPLEMDM_DEVICE_GROUP_CUSTOM_PROFILE_UPDATED_ID": fixtures.UpdatedCustomProfileID,
+		"SIMPLEMDM_DEVICE_ID":                              fixtures.DeviceID,
+		"SIMPLEMDM_ENROLLMENT_CONTACT":                     "it@example.com",
+		"SIMPLEMDM_ENROLLMENT_CONTACT_UPDATE":              "helpdesk@example.com",
+		"SIMPLEMDM_ENROLLMENT_ID":                          fixtures.EnrollmentID,
+		"SIMPLEMDM_PROFILE_ID":                             fixtures.ProfileID,
+		"SIMPLEMDM_SCRIPT_ID":                              fixtures.ScriptID,
+		"SIMPLEMDM_SCRIPT_JOB_ID":                          fixtures.ScriptJobID,
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
 	}
 }
 
@@ -52,7 +216,17 @@ func getTestClient() (*simplemdm.Client, error) {
 		host = "a.simplemdm.com"
 	}
 
-	return simplemdm.NewClient(host, apiKey), nil
+	options := simplemdmext.DefaultClientOptions()
+	options.Transport = apiTransport
+	if endpoint := os.Getenv("SIMPLEMDM_ENDPOINT"); endpoint != "" {
+		parsed, err := simplemdmext.ParseEndpoint(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SIMPLEMDM_ENDPOINT: %w", err)
+		}
+		options.Endpoint = parsed
+	}
+
+	return simplemdmext.NewClient(host, apiKey, options)
 }
 
 // testAccCheckDestroy is a helper to verify resource destruction